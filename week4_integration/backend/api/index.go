@@ -2,91 +2,150 @@ package api
 
 import (
 	"context"
-	"log"
+	"log/slog"
+	"math"
 	"net/http"
-	"os"
+	"strconv"
 	"sync"
+	"time"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/nedpals/supabase-go"
 
+	"backend/pkg/apierror"
+	"backend/pkg/auth"
+	"backend/pkg/config"
+	"backend/pkg/database"
 	"backend/pkg/handler"
+	"backend/pkg/i18n"
+	"backend/pkg/logging"
+	"backend/pkg/ratelimit"
+	"backend/pkg/tracing"
 )
 
 var (
-	db       *pgxpool.Pool
-	sb       *supabase.Client
+	cfg     *config.Config
+	cfgErr  error
+	cfgOnce sync.Once
+
+	db       *database.Lazy
+	ap       auth.Provider
 	dbOnce   sync.Once
-	sbOnce   sync.Once
+	authOnce sync.Once
+
+	router   http.Handler
+	routerMu sync.Mutex
 )
 
-func InitDB() *pgxpool.Pool {
-	dbOnce.Do(func() {
-		dbUrl := os.Getenv("DATABASE_URL")
-		var err error
-		db, err = pgxpool.New(context.Background(), dbUrl)
-		if err != nil {
-			log.Printf("DB Connection Error: %v", err)
+// InitConfig memvalidasi konfigurasi sekali. Error konfigurasi bersifat permanen
+// untuk instance ini, jadi hasilnya boleh di-cache.
+func InitConfig() (*config.Config, error) {
+	cfgOnce.Do(func() {
+		cfg, cfgErr = config.Load()
+		if cfgErr != nil {
+			slog.Error("invalid configuration", "err", cfgErr)
+			return
+		}
+		logging.Setup(cfg.Log)
+		if err := i18n.SetDefault(cfg.DefaultLanguage); err != nil {
+			slog.Error("invalid default language", "err", err)
+		}
+
+		// Tidak ada hook shutdown di Vercel; span dikirim lewat Flush tiap request
+		if _, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Env); err != nil {
+			slog.Error("tracing disabled", "err", err)
 		}
 	})
-	return db
+	return cfg, cfgErr
 }
 
-func InitSupabase() *supabase.Client {
-	sbOnce.Do(func() {
-		sbURL := os.Getenv("SUPABASE_URL")
-		sbKey := os.Getenv("SUPABASE_KEY")
-		sb = supabase.CreateClient(sbURL, sbKey)
+// InitDB hanya menyiapkan Lazy; koneksi dibuat (dan dicoba ulang) saat dibutuhkan.
+func InitDB(c *config.Config) *database.Lazy {
+	dbOnce.Do(func() {
+		db = database.NewLazy(c)
 	})
-	return sb
+	return db
 }
 
-func Handler(w http.ResponseWriter, r *http.Request) {
-	database := InitDB()
-	supabaseClient := InitSupabase()
+// InitAuth memilih provider login sekali. Mode standalone butuh pool, dan
+// schema auth-nya harus sudah dibuat lewat "migrate up".
+func InitAuth(c *config.Config, pool *pgxpool.Pool) auth.Provider {
+	authOnce.Do(func() {
+		ap = auth.New(c, pool)
+	})
+	return ap
+}
 
-	if database == nil || supabaseClient == nil {
-		http.Error(w, "Service Configuration Failed", 500)
-		return
+// InitRouter membangun router sekali per warm instance, bukan per request.
+// Kalau database belum bisa dihubungi, router belum dibuat dan request
+// berikutnya mencoba lagi.
+//
+// routerMu tidak dipegang selama connect: Lazy sudah memastikan hanya satu
+// request yang connect, dan connect-nya tidak ikut batal kalau client putus.
+func InitRouter(ctx context.Context) (http.Handler, error) {
+	routerMu.Lock()
+	h := router
+	routerMu.Unlock()
+	if h != nil {
+		return h, nil
 	}
 
-	h := handler.NewHttpServer(database, supabaseClient)
-	
-	router := chi.NewRouter()
-	router.Use(EnableCORS)
-
-	router.Post("/api/auth/register", h.HandleRegister)
-
-	router.Get("/api/products", h.HandleListPublicProducts)
-	router.Get("/api/products/{id}", h.HandleGetProductDetail)
+	c, err := InitConfig()
+	if err != nil {
+		return nil, err
+	}
 
-	router.Route("/api/admin", func(ar chi.Router) {
-		ar.Use(h.AdminOnly)
+	pool, err := InitDB(c).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		ar.Get("/products", h.HandleAdminListProducts)
-		ar.Post("/products", h.HandleCreateProduct)
-		ar.Put("/products/{id}", h.HandleUpdateProduct)
-		ar.Delete("/products/{id}", h.HandleDeleteProduct)
-		
-		ar.Get("/orders", h.HandleListOrders)
-		ar.Post("/orders", h.HandleCreateOrder)
-		ar.Put("/orders/{id}/status", h.HandleUpdateOrderStatus)
+	routerMu.Lock()
+	defer routerMu.Unlock()
+	if router != nil {
+		return router, nil
+	}
+	router = handler.NewRouter(handler.RouterDeps{
+		Config: c,
+		DB:     pool,
+		Auth:   InitAuth(c, pool),
+		// Instance serverless tidak berbagi memori, jadi bucket disimpan di Postgres
+		RateLimitStore: ratelimit.NewPostgresStore(pool),
+		TrustProxy:     true,
 	})
-
-	router.ServeHTTP(w, r)
+	return router, nil
 }
 
-func EnableCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
+func Handler(w http.ResponseWriter, r *http.Request) {
+	// Liveness tidak boleh ikut gagal hanya karena database belum terhubung
+	if r.URL.Path == "/healthz" {
+		handler.HandleHealthz(w, r)
+		return
+	}
+
+	h, err := InitRouter(r.Context())
+	if err != nil {
+		// Router (dan i18n.Middleware-nya) belum ada, jadi bahasa dipilih di sini
+		r = r.WithContext(i18n.WithLanguage(r.Context(), i18n.Match(r.Header.Get("Accept-Language"))))
+		if cfgErr != nil {
+			apierror.Write(w, r, apierror.New(http.StatusInternalServerError, "service_misconfigured", "Service configuration failed"))
 			return
 		}
-		next.ServeHTTP(w, r)
-	})
-}
\ No newline at end of file
+		// Gangguan database bersifat sementara, jadi client boleh mencoba lagi
+		retry := max(1, int(math.Ceil(db.RetryAfter().Seconds())))
+		w.Header().Set("Retry-After", strconv.Itoa(retry))
+		if r.URL.Path == "/readyz" {
+			handler.NotReady(w, err)
+			return
+		}
+		apierror.Write(w, r, apierror.New(http.StatusServiceUnavailable, apierror.CodeServiceUnavailable, "Database is not reachable, please retry later").
+			WithKey("error.service_unavailable.database", nil).Wrap(err))
+		return
+	}
+
+	h.ServeHTTP(w, r)
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	tracing.Flush(flushCtx)
+}
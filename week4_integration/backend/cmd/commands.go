@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"backend/pkg/logging"
+)
+
+// usageError dicetak apa adanya ke stderr (exit code 2), bukan lewat log.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// openDB memuat konfigurasi dan membuka pool untuk subcommand (migrate, seed).
+func openDB(ctx context.Context) (*config.Config, *pgxpool.Pool, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	logging.Setup(cfg.Log)
+
+	db, err := database.Connect(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, db, nil
+}
@@ -2,71 +2,148 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
-	"github.com/joho/godotenv"
-	"github.com/nedpals/supabase-go"
 
+	"backend/migrations"
+	"backend/pkg/auth"
+	"backend/pkg/config"
+	"backend/pkg/database"
 	"backend/pkg/handler"
+	"backend/pkg/i18n"
+	"backend/pkg/logging"
+	"backend/pkg/migrate"
+	"backend/pkg/ratelimit"
+	"backend/pkg/tracing"
 )
 
+// subcommands dijalankan lewat "main <nama> ...", tanpa argumen main
+// menjalankan HTTP server.
+var subcommands = map[string]func(args []string) error{
+	"migrate":       runMigrate,
+	"seed":          runSeed,
+	"promote-admin": runPromoteAdmin,
+}
+
 func main() {
-	_ = godotenv.Load("base.env")
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			err := cmd(os.Args[2:])
+			var usage usageError
+			if errors.As(err, &usage) {
+				fmt.Fprint(os.Stderr, usage)
+				os.Exit(2)
+			}
+			if err != nil {
+				slog.Error(os.Args[1]+" failed", "err", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	if err := run(); err != nil {
+		slog.Error("server failed", "err", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	logging.Setup(cfg.Log)
+	if err := i18n.SetDefault(cfg.DefaultLanguage); err != nil {
+		return err
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Env)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownTracing(ctx)
+	}()
 
-	dbUrl := os.Getenv("DATABASE_URL")
-	db, err := pgxpool.New(context.Background(), dbUrl)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.Connect(ctx, cfg)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer db.Close()
 
-	sbURL := os.Getenv("SUPABASE_URL")
-	sbKey := os.Getenv("SUPABASE_KEY")
-	sbClient := supabase.CreateClient(sbURL, sbKey)
+	if cfg.DB.MigrateOnStart {
+		if cfg.Auth.Mode == config.AuthStandalone {
+			if err := auth.Bootstrap(ctx, db); err != nil {
+				return err
+			}
+		}
+		m, err := migrate.New(db, migrations.FS)
+		if err != nil {
+			return err
+		}
+		if err := m.Up(ctx); err != nil {
+			return err
+		}
+	}
 
-	h := handler.NewHttpServer(db, sbClient)
+	r := handler.NewRouter(handler.RouterDeps{
+		Config:         cfg,
+		DB:             db,
+		Auth:           auth.New(cfg, db),
+		RateLimitStore: ratelimit.NewMemoryStore(),
+	})
 
-	r := chi.NewRouter()
-	r.Use(middleware.Logger)
-	r.Use(EnableCORS)
+	srv := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.HTTP.Port),
+		Handler:           r,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTP.MaxHeaderBytes,
+	}
 
-	r.Post("/api/auth/register", h.HandleRegister)
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server running", "port", cfg.HTTP.Port, "env", cfg.Env)
+		serveErr <- srv.ListenAndServe()
+	}()
 
-	r.Get("/api/products", h.HandleListPublicProducts)
-	r.Get("/api/products/{id}", h.HandleGetProductDetail)
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("listen: %w", err)
+	case <-ctx.Done():
+	}
 
-	r.Route("/api/admin", func(r chi.Router) {
-		r.Use(h.AdminOnly)
+	// Sinyal kedua langsung mematikan proses tanpa menunggu drain
+	stop()
+	slog.Info("shutting down, draining in-flight requests", "timeout", cfg.HTTP.ShutdownTimeout)
 
-		r.Get("/products", h.HandleAdminListProducts)
-		r.Post("/products", h.HandleCreateProduct)
-		r.Put("/products/{id}", h.HandleUpdateProduct)
-		r.Delete("/products/{id}", h.HandleDeleteProduct)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
 
-		r.Get("/orders", h.HandleListOrders)
-		r.Post("/orders", h.HandleCreateOrder)
-		r.Put("/orders/{id}/status", h.HandleUpdateOrderStatus)
-	})
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("listen: %w", err)
+	}
 
-	log.Println("Server running on port 8080")
-	http.ListenAndServe(":8080", r)
+	slog.Info("server stopped")
+	return nil
 }
-
-func EnableCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
-}
\ No newline at end of file
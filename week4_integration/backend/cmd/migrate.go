@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+
+	"backend/migrations"
+	"backend/pkg/auth"
+	"backend/pkg/config"
+	"backend/pkg/migrate"
+)
+
+const migrateUsage = usageError(`usage: main migrate <command>
+
+commands:
+  up         apply all pending migrations
+  down       roll back the last applied migration
+  status     list migrations and when they were applied
+  to N       migrate up or down to version N (0 drops everything)
+  force N    mark versions 1..N as applied without running them
+`)
+
+// runMigrate menangani "main migrate ...". Argumen dicek sebelum connect
+// supaya salah ketik tidak perlu menunggu database.
+func runMigrate(args []string) error {
+	if len(args) == 0 || !slices.Contains([]string{"up", "down", "status", "to", "force"}, args[0]) {
+		return migrateUsage
+	}
+	needsVersion := args[0] == "to" || args[0] == "force"
+	if needsVersion != (len(args) == 2) || len(args) > 2 {
+		return migrateUsage
+	}
+	var target int64
+	if needsVersion {
+		v, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || v < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		target = v
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, db, err := openDB(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// Mode standalone tidak punya auth.users dari Supabase, padahal migrasi
+	// pertama mereferensikannya
+	if cfg.Auth.Mode == config.AuthStandalone {
+		if err := auth.Bootstrap(ctx, db); err != nil {
+			return fmt.Errorf("auth bootstrap: %w", err)
+		}
+	}
+
+	m, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		return m.Down(ctx)
+	case "to":
+		return m.To(ctx, target)
+	case "force":
+		return m.Force(ctx, target)
+	default:
+		return printStatus(ctx, m)
+	}
+}
+
+func printStatus(ctx context.Context, m *migrate.Migrator) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.Applied() {
+			applied = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(tw, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return tw.Flush()
+}
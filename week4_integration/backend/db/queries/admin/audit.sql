@@ -0,0 +1,34 @@
+-- name: InsertAuditLog :exec
+INSERT INTO audit_log (
+    actor_id,
+    action,
+    entity_type,
+    entity_id,
+    before,
+    after,
+    request_id,
+    ip
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+);
+
+-- name: ListAuditLog :many
+-- Requirement: Mobile app audit log admin (filter actor/action/entity/rentang waktu + pagination)
+SELECT * FROM audit_log
+WHERE (sqlc.narg('actor_id')::uuid IS NULL OR actor_id = sqlc.narg('actor_id'))
+    AND (sqlc.arg('action')::text = '' OR action = sqlc.arg('action'))
+    AND (sqlc.arg('entity_type')::text = '' OR entity_type = sqlc.arg('entity_type'))
+    AND (sqlc.arg('entity_id')::text = '' OR entity_id = sqlc.arg('entity_id'))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR created_at >= sqlc.narg('since'))
+    AND (sqlc.narg('until')::timestamptz IS NULL OR created_at < sqlc.narg('until'))
+ORDER BY created_at DESC, audit_id DESC
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: CountAuditLog :one
+SELECT COUNT(*) FROM audit_log
+WHERE (sqlc.narg('actor_id')::uuid IS NULL OR actor_id = sqlc.narg('actor_id'))
+    AND (sqlc.arg('action')::text = '' OR action = sqlc.arg('action'))
+    AND (sqlc.arg('entity_type')::text = '' OR entity_type = sqlc.arg('entity_type'))
+    AND (sqlc.arg('entity_id')::text = '' OR entity_id = sqlc.arg('entity_id'))
+    AND (sqlc.narg('since')::timestamptz IS NULL OR created_at >= sqlc.narg('since'))
+    AND (sqlc.narg('until')::timestamptz IS NULL OR created_at < sqlc.narg('until'));
@@ -41,4 +41,10 @@ WHERE product_id = $1;
 -- name: DeleteProduct :exec
 -- Requirement: Mobile app delete product
 DELETE FROM products 
-WHERE product_id = $1;
\ No newline at end of file
+WHERE product_id = $1;
+
+-- name: GetProductForUpdate :one
+-- Snapshot sebelum update/delete untuk audit log
+SELECT * FROM products
+WHERE product_id = $1
+FOR UPDATE;
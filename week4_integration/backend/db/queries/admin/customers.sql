@@ -0,0 +1,67 @@
+-- name: ListCustomers :many
+-- Requirement: Mobile app daftar customer (search nama/username/no HP + pagination)
+SELECT 
+    user_id,
+    username,
+    full_name,
+    phone_number,
+    role,
+    city,
+    disabled_at,
+    created_at
+FROM users
+WHERE sqlc.arg('search')::text = ''
+    OR full_name ILIKE '%' || sqlc.arg('search') || '%'
+    OR username ILIKE '%' || sqlc.arg('search') || '%'
+    OR phone_number ILIKE '%' || sqlc.arg('search') || '%'
+ORDER BY created_at DESC, user_id ASC
+LIMIT sqlc.arg('page_size') OFFSET sqlc.arg('page_offset');
+
+-- name: CountCustomers :one
+SELECT COUNT(*) FROM users
+WHERE sqlc.arg('search')::text = ''
+    OR full_name ILIKE '%' || sqlc.arg('search') || '%'
+    OR username ILIKE '%' || sqlc.arg('search') || '%'
+    OR phone_number ILIKE '%' || sqlc.arg('search') || '%';
+
+-- name: GetCustomerDetail :one
+-- Requirement: Mobile app detail customer + total order dan total belanja (order 'done')
+SELECT 
+    u.user_id,
+    u.username,
+    u.full_name,
+    u.phone_number,
+    u.role,
+    u.street,
+    u.city,
+    u.post_code,
+    u.disabled_at,
+    u.created_at,
+    COUNT(o.order_id) AS order_count,
+    COALESCE(SUM(o.total_amount) FILTER (WHERE o.status = 'done'), 0)::numeric AS lifetime_spend
+FROM users u
+LEFT JOIN orders o ON o.user_id = u.user_id
+WHERE u.user_id = $1
+GROUP BY u.user_id;
+
+-- name: GetCustomerRoleForUpdate :one
+-- Role lama untuk audit log
+SELECT role FROM users
+WHERE user_id = $1
+FOR UPDATE;
+
+-- name: UpdateCustomerRole :execrows
+UPDATE users 
+SET role = $2, updated_at = NOW()
+WHERE user_id = $1;
+
+-- name: DisableCustomer :execrows
+-- PENTING: Soft disable, order milik customer tetap ada
+UPDATE users 
+SET disabled_at = COALESCE(disabled_at, NOW()), updated_at = NOW()
+WHERE user_id = $1;
+
+-- name: EnableCustomer :execrows
+UPDATE users 
+SET disabled_at = NULL, updated_at = NOW()
+WHERE user_id = $1;
@@ -8,7 +8,12 @@ SELECT
     u.full_name AS customer_name,
     u.phone_number,
     p.product_name,
-    p.image_url
+    p.image_url,
+    o.ship_recipient_name,
+    o.ship_phone_number,
+    o.ship_street,
+    o.ship_city,
+    o.ship_post_code
 FROM orders o
 JOIN users u ON o.user_id = u.user_id
 JOIN products p ON o.product_id = p.product_id
@@ -20,11 +25,38 @@ INSERT INTO orders (
     product_id,
     quantity,
     total_amount,
-    status
+    status,
+    address_id,
+    ship_recipient_name,
+    ship_phone_number,
+    ship_street,
+    ship_city,
+    ship_post_code
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 );
 
+-- name: GetUserAddress :one
+-- PENTING: Dipakai Go untuk snapshot alamat pengiriman order
+SELECT * FROM addresses
+WHERE address_id = $1 AND user_id = $2;
+
+-- name: GetDefaultAddress :one
+SELECT * FROM addresses
+WHERE user_id = $1 AND is_default;
+
+-- name: GetProfileShipping :one
+-- PENTING: Dipakai Go sebagai snapshot alamat kalau user belum punya alamat default
+SELECT full_name, phone_number, street, city, post_code
+FROM users
+WHERE user_id = $1;
+
+-- name: GetOrderStatusForUpdate :one
+-- PENTING: Dipakai Go untuk mencatat transisi status (from -> to) di metrics
+SELECT status FROM orders
+WHERE order_id = $1
+FOR UPDATE;
+
 -- name: UpdateOrderStatus :exec
 UPDATE orders 
 SET status = $2 
@@ -36,8 +68,9 @@ SELECT product_id, quantity
 FROM orders 
 WHERE order_id = $1;
 
--- name: DecreaseProductStock :exec
+-- name: DecreaseProductStock :one
 -- PENTING: Dipakai Go saat status berubah jadi 'paid'
 UPDATE products 
 SET stock = stock - $2 
-WHERE product_id = $1;
\ No newline at end of file
+WHERE product_id = $1
+RETURNING stock;
\ No newline at end of file
@@ -0,0 +1,52 @@
+-- name: ListUserAddresses :many
+-- Requirement: Web buku alamat di halaman profile
+SELECT * FROM addresses
+WHERE user_id = $1
+ORDER BY is_default DESC, address_id ASC;
+
+-- name: CountUserAddresses :one
+SELECT COUNT(*) FROM addresses
+WHERE user_id = $1;
+
+-- name: CreateAddress :one
+INSERT INTO addresses (
+    user_id,
+    label,
+    recipient_name,
+    phone_number,
+    street,
+    city,
+    post_code,
+    is_default
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: UpdateAddress :one
+UPDATE addresses
+SET
+    label = $3,
+    recipient_name = $4,
+    phone_number = $5,
+    street = $6,
+    city = $7,
+    post_code = $8,
+    updated_at = NOW()
+WHERE address_id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteAddress :execrows
+DELETE FROM addresses
+WHERE address_id = $1 AND user_id = $2;
+
+-- name: ClearDefaultAddress :exec
+-- PENTING: Dipanggil dalam transaksi yang sama sebelum SetDefaultAddress
+UPDATE addresses
+SET is_default = FALSE, updated_at = NOW()
+WHERE user_id = $1 AND is_default;
+
+-- name: SetDefaultAddress :execrows
+UPDATE addresses
+SET is_default = TRUE, updated_at = NOW()
+WHERE address_id = $1 AND user_id = $2;
@@ -0,0 +1,33 @@
+-- name: GetUserProfile :one
+-- Requirement: Web & mobile fetch profile + role setelah login
+SELECT 
+    user_id,
+    username,
+    full_name,
+    phone_number,
+    role,
+    street,
+    city,
+    post_code,
+    disabled_at
+FROM users
+WHERE user_id = $1;
+
+-- name: UpdateUserProfile :one
+-- Requirement: Web edit profile (PATCH, field yang tidak dikirim tidak berubah)
+UPDATE users
+SET
+    username = COALESCE(sqlc.narg('username'), username),
+    full_name = COALESCE(sqlc.narg('full_name'), full_name),
+    phone_number = COALESCE(sqlc.narg('phone_number'), phone_number),
+    updated_at = NOW()
+WHERE user_id = sqlc.arg('user_id')
+RETURNING 
+    user_id,
+    username,
+    full_name,
+    phone_number,
+    role,
+    street,
+    city,
+    post_code;
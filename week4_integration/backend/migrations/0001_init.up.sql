@@ -52,4 +52,4 @@ ALTER TABLE orders ENABLE ROW LEVEL SECURITY;
 -- Indexing
 CREATE INDEX idx_users_username ON users(username);
 CREATE INDEX idx_products_category ON products(category);
-CREATE INDEX idx_orders_user ON orders(user_id);
\ No newline at end of file
+CREATE INDEX idx_orders_user ON orders(user_id);
@@ -0,0 +1,9 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS address_id,
+    DROP COLUMN IF EXISTS ship_recipient_name,
+    DROP COLUMN IF EXISTS ship_phone_number,
+    DROP COLUMN IF EXISTS ship_street,
+    DROP COLUMN IF EXISTS ship_city,
+    DROP COLUMN IF EXISTS ship_post_code;
+
+DROP TABLE IF EXISTS addresses;
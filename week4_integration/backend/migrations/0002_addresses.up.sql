@@ -0,0 +1,38 @@
+-- Tabel Addresses (buku alamat pengiriman, satu user bisa punya banyak)
+CREATE TABLE addresses (
+    address_id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    
+    label VARCHAR(50) NOT NULL,
+    recipient_name VARCHAR(100) NOT NULL,
+    phone_number VARCHAR(20) NOT NULL,
+    street VARCHAR(100) NOT NULL,
+    city VARCHAR(50) NOT NULL,
+    post_code VARCHAR(10) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- Snapshot alamat saat order dibuat, tidak ikut berubah kalau address diedit/dihapus
+ALTER TABLE orders
+    ADD COLUMN address_id INT REFERENCES addresses(address_id) ON DELETE SET NULL,
+    ADD COLUMN ship_recipient_name VARCHAR(100),
+    ADD COLUMN ship_phone_number VARCHAR(20),
+    ADD COLUMN ship_street VARCHAR(100),
+    ADD COLUMN ship_city VARCHAR(50),
+    ADD COLUMN ship_post_code VARCHAR(10);
+
+ALTER TABLE addresses ENABLE ROW LEVEL SECURITY;
+
+CREATE INDEX idx_addresses_user ON addresses(user_id);
+CREATE UNIQUE INDEX uq_addresses_default ON addresses(user_id) WHERE is_default;
+
+-- Alamat di profil user lama jadi alamat default pertama di buku alamat
+INSERT INTO addresses (user_id, label, recipient_name, phone_number, street, city, post_code, is_default)
+SELECT user_id, 'Rumah', full_name, phone_number, street, city, post_code, TRUE
+FROM users
+WHERE street IS NOT NULL AND street <> '';
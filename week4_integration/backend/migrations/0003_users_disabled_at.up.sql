@@ -0,0 +1,2 @@
+-- Soft disable: akun dinonaktifkan tanpa menghapus histori order
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMP;
@@ -0,0 +1,9 @@
+-- Tabel Rate Limits (token bucket bersama untuk instance serverless)
+CREATE UNLOGGED TABLE rate_limits (
+    bucket_key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE rate_limits ENABLE ROW LEVEL SECURITY;
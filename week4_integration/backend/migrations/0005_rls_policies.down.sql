@@ -0,0 +1,18 @@
+-- Role anon/authenticated dan GRANT dibiarkan: di Supabase keduanya bawaan
+-- platform, bukan milik migrasi ini.
+DROP POLICY addresses_admin_all ON addresses;
+DROP POLICY addresses_own ON addresses;
+DROP POLICY orders_admin_all ON orders;
+DROP POLICY orders_read_own ON orders;
+
+DROP TRIGGER users_guard_admin_columns ON users;
+DROP FUNCTION users_guard_admin_columns();
+
+DROP POLICY users_admin_all ON users;
+DROP POLICY users_update_own ON users;
+DROP POLICY users_read_own ON users;
+DROP POLICY products_admin_all ON products;
+DROP POLICY products_public_read ON products;
+
+DROP FUNCTION app_is_admin();
+DROP FUNCTION app_user_id();
@@ -0,0 +1,96 @@
+-- Policy RLS yang sama dengan otorisasi API: publik hanya melihat produk yang
+-- stoknya ada, user melihat profil/order miliknya dan mengelola alamatnya,
+-- admin boleh semuanya. Berlaku untuk client Supabase maupun backend, yang
+-- menjalankan query dengan SET LOCAL role + request.jwt.claims.
+
+-- Supabase sudah punya role anon/authenticated; Postgres biasa belum
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'anon') THEN
+        CREATE ROLE anon NOLOGIN NOINHERIT;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'authenticated') THEN
+        CREATE ROLE authenticated NOLOGIN NOINHERIT;
+    END IF;
+    -- Role koneksi backend harus boleh SET ROLE ke keduanya
+    IF NOT pg_has_role(current_user, 'anon', 'MEMBER') THEN
+        EXECUTE format('GRANT anon TO %I', current_user);
+    END IF;
+    IF NOT pg_has_role(current_user, 'authenticated', 'MEMBER') THEN
+        EXECUTE format('GRANT authenticated TO %I', current_user);
+    END IF;
+END
+$$;
+
+GRANT USAGE ON SCHEMA public TO anon, authenticated;
+GRANT SELECT ON products TO anon;
+GRANT SELECT, INSERT, UPDATE, DELETE ON users, products, orders, addresses TO authenticated;
+GRANT USAGE ON SEQUENCE products_product_id_seq, orders_order_id_seq, addresses_address_id_seq TO authenticated;
+
+-- Sama seperti auth.uid() milik Supabase, tapi ada juga di Postgres biasa
+CREATE FUNCTION app_user_id() RETURNS UUID
+LANGUAGE sql STABLE AS $$
+    SELECT (NULLIF(current_setting('request.jwt.claims', true), '')::jsonb ->> 'sub')::uuid
+$$;
+
+-- SECURITY DEFINER supaya policy di tabel users tidak memanggil dirinya sendiri
+CREATE FUNCTION app_is_admin() RETURNS BOOLEAN
+LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM users
+        WHERE user_id = app_user_id() AND role = 'admin' AND disabled_at IS NULL
+    )
+$$;
+
+-- Products
+CREATE POLICY products_public_read ON products
+    FOR SELECT TO anon, authenticated
+    USING (stock > 0);
+CREATE POLICY products_admin_all ON products
+    FOR ALL TO authenticated
+    USING (app_is_admin()) WITH CHECK (app_is_admin());
+
+-- Users
+CREATE POLICY users_read_own ON users
+    FOR SELECT TO authenticated
+    USING (user_id = app_user_id());
+CREATE POLICY users_update_own ON users
+    FOR UPDATE TO authenticated
+    USING (user_id = app_user_id()) WITH CHECK (user_id = app_user_id());
+CREATE POLICY users_admin_all ON users
+    FOR ALL TO authenticated
+    USING (app_is_admin()) WITH CHECK (app_is_admin());
+
+-- Policy tidak bisa membandingkan baris lama dan baru, jadi kolom yang hanya
+-- boleh diubah admin dijaga trigger
+CREATE FUNCTION users_guard_admin_columns() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF current_user IN ('anon', 'authenticated') AND NOT app_is_admin()
+        AND (NEW.user_id, NEW.role, NEW.disabled_at) IS DISTINCT FROM (OLD.user_id, OLD.role, OLD.disabled_at) THEN
+        RAISE EXCEPTION 'only admins can change user_id, role or disabled_at'
+            USING ERRCODE = 'insufficient_privilege';
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER users_guard_admin_columns
+    BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION users_guard_admin_columns();
+
+-- Orders
+CREATE POLICY orders_read_own ON orders
+    FOR SELECT TO authenticated
+    USING (user_id = app_user_id());
+CREATE POLICY orders_admin_all ON orders
+    FOR ALL TO authenticated
+    USING (app_is_admin()) WITH CHECK (app_is_admin());
+
+-- Addresses
+CREATE POLICY addresses_own ON addresses
+    FOR ALL TO authenticated
+    USING (user_id = app_user_id()) WITH CHECK (user_id = app_user_id());
+CREATE POLICY addresses_admin_all ON addresses
+    FOR ALL TO authenticated
+    USING (app_is_admin()) WITH CHECK (app_is_admin());
@@ -0,0 +1,35 @@
+-- Tabel Audit Log (perubahan oleh admin, ditulis dalam transaksi yang sama
+-- dengan perubahannya). actor_id sengaja tanpa foreign key supaya histori
+-- tetap ada walaupun akun admin dihapus.
+CREATE TABLE audit_log (
+    audit_id BIGSERIAL PRIMARY KEY,
+    actor_id UUID,
+
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id TEXT NOT NULL,
+    -- Hanya field yang berubah; create tanpa before, delete tanpa after
+    before JSONB,
+    after JSONB,
+
+    request_id TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE audit_log ENABLE ROW LEVEL SECURITY;
+
+-- Append-only lewat API: tidak ada policy UPDATE/DELETE
+GRANT SELECT, INSERT ON audit_log TO authenticated;
+GRANT USAGE ON SEQUENCE audit_log_audit_id_seq TO authenticated;
+
+CREATE POLICY audit_log_admin_read ON audit_log
+    FOR SELECT TO authenticated
+    USING (app_is_admin());
+CREATE POLICY audit_log_admin_insert ON audit_log
+    FOR INSERT TO authenticated
+    WITH CHECK (app_is_admin() AND actor_id = app_user_id());
+
+CREATE INDEX idx_audit_log_created ON audit_log(created_at DESC);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_id);
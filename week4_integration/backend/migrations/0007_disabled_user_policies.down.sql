@@ -0,0 +1,11 @@
+DROP POLICY addresses_own ON addresses;
+CREATE POLICY addresses_own ON addresses
+    FOR ALL TO authenticated
+    USING (user_id = app_user_id()) WITH CHECK (user_id = app_user_id());
+
+DROP POLICY users_update_own ON users;
+CREATE POLICY users_update_own ON users
+    FOR UPDATE TO authenticated
+    USING (user_id = app_user_id()) WITH CHECK (user_id = app_user_id());
+
+DROP FUNCTION app_is_active();
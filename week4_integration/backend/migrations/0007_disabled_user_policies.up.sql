@@ -0,0 +1,23 @@
+-- Akun yang dinonaktifkan tidak boleh lagi mengubah profil atau mengelola
+-- alamatnya, walaupun access token lamanya belum expired.
+
+-- SECURITY DEFINER karena dipakai policy tabel addresses dan membaca users
+CREATE FUNCTION app_is_active() RETURNS BOOLEAN
+LANGUAGE sql STABLE SECURITY DEFINER SET search_path = public AS $$
+    SELECT EXISTS (
+        SELECT 1 FROM users
+        WHERE user_id = app_user_id() AND disabled_at IS NULL
+    )
+$$;
+
+DROP POLICY users_update_own ON users;
+CREATE POLICY users_update_own ON users
+    FOR UPDATE TO authenticated
+    USING (user_id = app_user_id() AND disabled_at IS NULL)
+    WITH CHECK (user_id = app_user_id() AND disabled_at IS NULL);
+
+DROP POLICY addresses_own ON addresses;
+CREATE POLICY addresses_own ON addresses
+    FOR ALL TO authenticated
+    USING (user_id = app_user_id() AND app_is_active())
+    WITH CHECK (user_id = app_user_id() AND app_is_active());
@@ -0,0 +1,11 @@
+// Package migrations berisi migrasi schema bernomor yang di-embed ke binary.
+//
+// Setiap versi punya dua file: NNNN_nama.up.sql dan NNNN_nama.down.sql.
+// Nomor tidak boleh diubah setelah dirilis; perubahan schema selalu berupa
+// file baru. sqlc membaca folder ini (file .down.sql diabaikan) sebagai schema.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,199 @@
+// Package apierror adalah satu-satunya format error yang dikirim API ke client:
+//
+//	{"error": {"code": "product_not_found", "message": "...", "details": [...], "request_id": "..."}}
+//
+// Code bersifat stabil dan boleh dipakai client untuk logika; message hanya
+// untuk manusia dan diterjemahkan sesuai Accept-Language lewat katalog i18n
+// (key "error.<code>"; Message dipakai kalau key tidak ada). Error internal
+// (termasuk pesan Postgres) dicatat di log, tidak pernah dikirim ke client.
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"backend/pkg/i18n"
+	"backend/pkg/logging"
+)
+
+// Code umum. Handler boleh memakai code yang lebih spesifik seperti
+// "product_not_found" lewat New.
+const (
+	CodeInvalidJSON        = "invalid_json"
+	CodeInvalidID          = "invalid_id"
+	CodeValidationFailed   = "validation_failed"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodeAlreadyExists      = "already_exists"
+	CodeResourceInUse      = "resource_in_use"
+	CodeInvalidReference   = "invalid_reference"
+	CodeConstraintViolated = "constraint_violation"
+	CodeInsufficientStock  = "insufficient_stock"
+	CodeRateLimited        = "rate_limited"
+	CodeServiceUnavailable = "service_unavailable"
+	CodeInternal           = "internal_error"
+)
+
+// FieldError menjelaskan masalah pada satu field request. Key dan Params
+// dipakai untuk menerjemahkan Message, mis. "validation.min_length" + {param: 6}.
+type FieldError struct {
+	Field   string            `json:"field"`
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Key     string            `json:"-"`
+	Params  map[string]string `json:"-"`
+}
+
+type Error struct {
+	Status  int
+	Code    string
+	Message string
+	Details []FieldError
+
+	// Key menggantikan "error.<Code>" kalau satu code punya beberapa pesan.
+	Key    string
+	Params map[string]string
+
+	// Err adalah penyebab asli, hanya untuk log.
+	Err error
+}
+
+func New(status int, code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Message + ": " + e.Err.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Wrap menyimpan penyebab asli tanpa mengubah apa yang dilihat client.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithKey memilih key terjemahan lain untuk code yang sama, mis.
+// "error.unauthorized.missing_token".
+func (e *Error) WithKey(key string, params map[string]string) *Error {
+	c := *e
+	c.Key, c.Params = key, params
+	return &c
+}
+
+func (e *Error) WithDetails(details ...FieldError) *Error {
+	c := *e
+	c.Details = append(append([]FieldError{}, e.Details...), details...)
+	return &c
+}
+
+func BadRequest(code, message string) *Error {
+	return New(http.StatusBadRequest, code, message)
+}
+
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return New(http.StatusForbidden, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return New(http.StatusNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return New(http.StatusConflict, code, message)
+}
+
+// Validation dipakai kalau satu atau lebih field tidak valid.
+func Validation(details ...FieldError) *Error {
+	return New(http.StatusUnprocessableEntity, CodeValidationFailed, "Request validation failed").WithDetails(details...)
+}
+
+var (
+	ErrInvalidJSON = BadRequest(CodeInvalidJSON, "Request body is not valid JSON")
+	ErrInternal    = New(http.StatusInternalServerError, CodeInternal, "Internal server error")
+)
+
+// From mengubah error apa pun menjadi *Error: *Error dipakai apa adanya,
+// error Postgres dipetakan ke 4xx yang sesuai, sisanya jadi 500.
+func From(err error) *Error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	if dbErr := fromPostgres(err); dbErr != nil {
+		return dbErr
+	}
+	return ErrInternal.Wrap(err)
+}
+
+type envelope struct {
+	Error body `json:"error"`
+}
+
+type body struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+}
+
+// Write menulis error sebagai JSON envelope dan mencatat penyebabnya.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	e := From(err)
+
+	if e.Status >= 500 {
+		slog.ErrorContext(r.Context(), "request failed", "code", e.Code, logging.Err(err))
+	} else if e.Err != nil {
+		slog.DebugContext(r.Context(), "request rejected", "code", e.Code, logging.Err(e.Err))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(envelope{Error: body{
+		Code:      e.Code,
+		Message:   e.localize(r.Context()),
+		Details:   localizeDetails(r.Context(), e.Details),
+		RequestID: logging.RequestID(r.Context()),
+	}})
+}
+
+func (e *Error) localize(ctx context.Context) string {
+	key := e.Key
+	if key == "" {
+		key = "error." + e.Code
+	}
+	if msg, ok := i18n.Lookup(ctx, key, e.Params); ok {
+		return msg
+	}
+	return e.Message
+}
+
+func localizeDetails(ctx context.Context, details []FieldError) []FieldError {
+	out := make([]FieldError, len(details))
+	for i, d := range details {
+		key := d.Key
+		if key == "" {
+			key = "validation." + d.Code
+		}
+		if msg, ok := i18n.Lookup(ctx, key, d.Params); ok {
+			d.Message = msg
+		}
+		out[i] = d
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package apierror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"backend/pkg/apierror"
+	"backend/pkg/i18n"
+)
+
+func TestFromPostgres(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"no rows", pgx.ErrNoRows, http.StatusNotFound, apierror.CodeNotFound},
+		{"known constraint", &pgconn.PgError{Code: "23514", ConstraintName: "products_stock_check"}, http.StatusConflict, apierror.CodeInsufficientStock},
+		{"unique username", &pgconn.PgError{Code: "23505", ConstraintName: "users_username_key"}, http.StatusConflict, apierror.CodeAlreadyExists},
+		{"other unique", &pgconn.PgError{Code: "23505", ConstraintName: "products_pkey"}, http.StatusConflict, apierror.CodeAlreadyExists},
+		// Pesan (yang ikut lc_messages) tidak mengubah hasil
+		{"foreign key", &pgconn.PgError{Code: "23503", Message: "update or delete on table \"products\" violates foreign key constraint"}, http.StatusUnprocessableEntity, apierror.CodeInvalidReference},
+		{"foreign key localized", &pgconn.PgError{Code: "23503", Message: "la inserción o actualización en la tabla «orders» viola la llave foránea"}, http.StatusUnprocessableEntity, apierror.CodeInvalidReference},
+		{"not null", &pgconn.PgError{Code: "23502"}, http.StatusUnprocessableEntity, apierror.CodeConstraintViolated},
+		{"bad value", &pgconn.PgError{Code: "22P02"}, http.StatusBadRequest, apierror.CodeValidationFailed},
+		{"rls", &pgconn.PgError{Code: "42501"}, http.StatusForbidden, apierror.CodeForbidden},
+		{"serialization", &pgconn.PgError{Code: "40001"}, http.StatusConflict, apierror.CodeConflict},
+		{"unmapped sqlstate", &pgconn.PgError{Code: "53300"}, http.StatusInternalServerError, apierror.CodeInternal},
+		{"plain error", errors.New("boom"), http.StatusInternalServerError, apierror.CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Dibungkus seperti error dari service
+			err := fmt.Errorf("query: %w", tt.err)
+			got := apierror.From(err)
+			if got.Status != tt.status || got.Code != tt.code {
+				t.Errorf("From = %d %s, want %d %s", got.Status, got.Code, tt.status, tt.code)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("cause %v not kept", tt.err)
+			}
+		})
+	}
+}
+
+// *Error yang sudah ada dipakai apa adanya, bukan dipetakan ulang
+func TestFromKeepsAPIError(t *testing.T) {
+	inUse := apierror.Conflict(apierror.CodeResourceInUse, "Resource is still referenced by other records")
+	err := inUse.Wrap(&pgconn.PgError{Code: "23503"})
+	if got := apierror.From(fmt.Errorf("delete: %w", err)); got.Code != apierror.CodeResourceInUse {
+		t.Errorf("code = %s, want %s", got.Code, apierror.CodeResourceInUse)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	err := apierror.Validation(apierror.FieldError{Field: "email", Code: "email", Message: "must be a valid email"}).
+		Wrap(errors.New("internal detail"))
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r = r.WithContext(i18n.WithLanguage(r.Context(), i18n.Indonesian))
+	w := httptest.NewRecorder()
+	apierror.Write(w, r, err)
+
+	var out struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+			Details []struct {
+				Field   string `json:"field"`
+				Message string `json:"message"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusUnprocessableEntity || out.Error.Code != apierror.CodeValidationFailed {
+		t.Fatalf("status = %d, code = %s", w.Code, out.Error.Code)
+	}
+	want, _ := i18n.Lookup(r.Context(), "error.validation_failed", nil)
+	if out.Error.Message != want {
+		t.Errorf("message = %q, want %q", out.Error.Message, want)
+	}
+	if len(out.Error.Details) != 1 || out.Error.Details[0].Message == "must be a valid email" {
+		t.Errorf("details = %+v, want translated", out.Error.Details)
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("content type = %q", w.Header().Get("Content-Type"))
+	}
+}
@@ -0,0 +1,54 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// constraintErrors memberi code khusus untuk constraint yang artinya jelas
+// bagi client. Constraint lain jatuh ke pemetaan umum per SQLSTATE.
+var constraintErrors = map[string]*Error{
+	"products_stock_check": Conflict(CodeInsufficientStock, "Not enough stock for this product"),
+	"uq_addresses_default": Conflict(CodeConflict, "User already has a default address").WithKey("error.conflict.default_address", nil),
+	"users_username_key":   Conflict(CodeAlreadyExists, "Username is already taken").WithKey("error.already_exists.username", nil),
+}
+
+func fromPostgres(err error) *Error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NotFound(CodeNotFound, "Resource not found").Wrap(err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+
+	if e, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return e.Wrap(err)
+	}
+
+	var e *Error
+	switch pgErr.Code {
+	case "23505": // unique_violation
+		e = Conflict(CodeAlreadyExists, "Resource already exists")
+	case "23503": // foreign_key_violation
+		// SQLSTATE dan nama constraint sama untuk insert yang merujuk baris yang
+		// tidak ada maupun delete baris yang masih dirujuk, dan pesannya ikut
+		// lc_messages. Jalur delete memetakan sendiri ke resource_in_use.
+		e = New(http.StatusUnprocessableEntity, CodeInvalidReference, "Referenced resource does not exist")
+	case "23514", "23502": // check_violation, not_null_violation
+		e = New(http.StatusUnprocessableEntity, CodeConstraintViolated, "Request violates a data constraint")
+	case "22P02", "22001", "22003": // invalid_text_representation, string_data_right_truncation, numeric_value_out_of_range
+		e = BadRequest(CodeValidationFailed, "Request contains an invalid value").WithKey("error.validation_failed.invalid_value", nil)
+	case "42501": // insufficient_privilege, termasuk pelanggaran policy RLS
+		e = Forbidden(CodeForbidden, "You are not allowed to do this")
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		e = Conflict(CodeConflict, "Concurrent update, please retry").WithKey("error.conflict.retry", nil)
+	default:
+		return nil
+	}
+	return e.Wrap(err)
+}
@@ -60,6 +60,30 @@ func (q *Queries) DeleteProduct(ctx context.Context, productID int32) error {
 	return err
 }
 
+const getProductForUpdate = `-- name: GetProductForUpdate :one
+SELECT product_id, product_name, category, description, unit_price, image_url, stock, created_at, updated_at FROM products
+WHERE product_id = $1
+FOR UPDATE
+`
+
+// Snapshot sebelum update/delete untuk audit log
+func (q *Queries) GetProductForUpdate(ctx context.Context, productID int32) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductForUpdate, productID)
+	var i Product
+	err := row.Scan(
+		&i.ProductID,
+		&i.ProductName,
+		&i.Category,
+		&i.Description,
+		&i.UnitPrice,
+		&i.ImageUrl,
+		&i.Stock,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const listAllProductsAdmin = `-- name: ListAllProductsAdmin :many
 SELECT 
     image_url, 
@@ -8,14 +8,47 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Address struct {
+	AddressID     int32            `json:"address_id"`
+	UserID        pgtype.UUID      `json:"user_id"`
+	Label         string           `json:"label"`
+	RecipientName string           `json:"recipient_name"`
+	PhoneNumber   string           `json:"phone_number"`
+	Street        string           `json:"street"`
+	City          string           `json:"city"`
+	PostCode      string           `json:"post_code"`
+	IsDefault     bool             `json:"is_default"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+type AuditLog struct {
+	AuditID    int64              `json:"audit_id"`
+	ActorID    pgtype.UUID        `json:"actor_id"`
+	Action     string             `json:"action"`
+	EntityType string             `json:"entity_type"`
+	EntityID   string             `json:"entity_id"`
+	Before     []byte             `json:"before"`
+	After      []byte             `json:"after"`
+	RequestID  string             `json:"request_id"`
+	Ip         string             `json:"ip"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
 type Order struct {
-	OrderID     int32            `json:"order_id"`
-	UserID      pgtype.UUID      `json:"user_id"`
-	ProductID   int32            `json:"product_id"`
-	Quantity    int32            `json:"quantity"`
-	TotalAmount pgtype.Numeric   `json:"total_amount"`
-	Status      string           `json:"status"`
-	OrderDate   pgtype.Timestamp `json:"order_date"`
+	OrderID           int32            `json:"order_id"`
+	UserID            pgtype.UUID      `json:"user_id"`
+	ProductID         int32            `json:"product_id"`
+	Quantity          int32            `json:"quantity"`
+	TotalAmount       pgtype.Numeric   `json:"total_amount"`
+	Status            string           `json:"status"`
+	OrderDate         pgtype.Timestamp `json:"order_date"`
+	AddressID         pgtype.Int4      `json:"address_id"`
+	ShipRecipientName pgtype.Text      `json:"ship_recipient_name"`
+	ShipPhoneNumber   pgtype.Text      `json:"ship_phone_number"`
+	ShipStreet        pgtype.Text      `json:"ship_street"`
+	ShipCity          pgtype.Text      `json:"ship_city"`
+	ShipPostCode      pgtype.Text      `json:"ship_post_code"`
 }
 
 type Product struct {
@@ -30,6 +63,13 @@ type Product struct {
 	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
 }
 
+type RateLimit struct {
+	BucketKey string             `json:"bucket_key"`
+	Tokens    float64            `json:"tokens"`
+	Allowed   bool               `json:"allowed"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
 type User struct {
 	UserID      pgtype.UUID      `json:"user_id"`
 	Username    string           `json:"username"`
@@ -41,4 +81,5 @@ type User struct {
 	PostCode    string           `json:"post_code"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
 	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
+	DisabledAt  pgtype.Timestamp `json:"disabled_at"`
 }
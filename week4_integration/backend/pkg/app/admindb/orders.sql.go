@@ -17,18 +17,30 @@ INSERT INTO orders (
     product_id,
     quantity,
     total_amount,
-    status
+    status,
+    address_id,
+    ship_recipient_name,
+    ship_phone_number,
+    ship_street,
+    ship_city,
+    ship_post_code
 ) VALUES (
-    $1, $2, $3, $4, $5
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 )
 `
 
 type CreateOrderParams struct {
-	UserID      pgtype.UUID    `json:"user_id"`
-	ProductID   int32          `json:"product_id"`
-	Quantity    int32          `json:"quantity"`
-	TotalAmount pgtype.Numeric `json:"total_amount"`
-	Status      string         `json:"status"`
+	UserID            pgtype.UUID    `json:"user_id"`
+	ProductID         int32          `json:"product_id"`
+	Quantity          int32          `json:"quantity"`
+	TotalAmount       pgtype.Numeric `json:"total_amount"`
+	Status            string         `json:"status"`
+	AddressID         pgtype.Int4    `json:"address_id"`
+	ShipRecipientName pgtype.Text    `json:"ship_recipient_name"`
+	ShipPhoneNumber   pgtype.Text    `json:"ship_phone_number"`
+	ShipStreet        pgtype.Text    `json:"ship_street"`
+	ShipCity          pgtype.Text    `json:"ship_city"`
+	ShipPostCode      pgtype.Text    `json:"ship_post_code"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) error {
@@ -38,14 +50,21 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) error
 		arg.Quantity,
 		arg.TotalAmount,
 		arg.Status,
+		arg.AddressID,
+		arg.ShipRecipientName,
+		arg.ShipPhoneNumber,
+		arg.ShipStreet,
+		arg.ShipCity,
+		arg.ShipPostCode,
 	)
 	return err
 }
 
-const decreaseProductStock = `-- name: DecreaseProductStock :exec
+const decreaseProductStock = `-- name: DecreaseProductStock :one
 UPDATE products 
 SET stock = stock - $2 
 WHERE product_id = $1
+RETURNING stock
 `
 
 type DecreaseProductStockParams struct {
@@ -54,9 +73,35 @@ type DecreaseProductStockParams struct {
 }
 
 // PENTING: Dipakai Go saat status berubah jadi 'paid'
-func (q *Queries) DecreaseProductStock(ctx context.Context, arg DecreaseProductStockParams) error {
-	_, err := q.db.Exec(ctx, decreaseProductStock, arg.ProductID, arg.Stock)
-	return err
+func (q *Queries) DecreaseProductStock(ctx context.Context, arg DecreaseProductStockParams) (int32, error) {
+	row := q.db.QueryRow(ctx, decreaseProductStock, arg.ProductID, arg.Stock)
+	var stock int32
+	err := row.Scan(&stock)
+	return stock, err
+}
+
+const getDefaultAddress = `-- name: GetDefaultAddress :one
+SELECT address_id, user_id, label, recipient_name, phone_number, street, city, post_code, is_default, created_at, updated_at FROM addresses
+WHERE user_id = $1 AND is_default
+`
+
+func (q *Queries) GetDefaultAddress(ctx context.Context, userID pgtype.UUID) (Address, error) {
+	row := q.db.QueryRow(ctx, getDefaultAddress, userID)
+	var i Address
+	err := row.Scan(
+		&i.AddressID,
+		&i.UserID,
+		&i.Label,
+		&i.RecipientName,
+		&i.PhoneNumber,
+		&i.Street,
+		&i.City,
+		&i.PostCode,
+		&i.IsDefault,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
 const getOrderQuantityAndProduct = `-- name: GetOrderQuantityAndProduct :one
@@ -78,6 +123,78 @@ func (q *Queries) GetOrderQuantityAndProduct(ctx context.Context, orderID int32)
 	return i, err
 }
 
+const getOrderStatusForUpdate = `-- name: GetOrderStatusForUpdate :one
+SELECT status FROM orders
+WHERE order_id = $1
+FOR UPDATE
+`
+
+// PENTING: Dipakai Go untuk mencatat transisi status (from -> to) di metrics
+func (q *Queries) GetOrderStatusForUpdate(ctx context.Context, orderID int32) (string, error) {
+	row := q.db.QueryRow(ctx, getOrderStatusForUpdate, orderID)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const getProfileShipping = `-- name: GetProfileShipping :one
+SELECT full_name, phone_number, street, city, post_code
+FROM users
+WHERE user_id = $1
+`
+
+type GetProfileShippingRow struct {
+	FullName    string `json:"full_name"`
+	PhoneNumber string `json:"phone_number"`
+	Street      string `json:"street"`
+	City        string `json:"city"`
+	PostCode    string `json:"post_code"`
+}
+
+// PENTING: Dipakai Go sebagai snapshot alamat kalau user belum punya alamat default
+func (q *Queries) GetProfileShipping(ctx context.Context, userID pgtype.UUID) (GetProfileShippingRow, error) {
+	row := q.db.QueryRow(ctx, getProfileShipping, userID)
+	var i GetProfileShippingRow
+	err := row.Scan(
+		&i.FullName,
+		&i.PhoneNumber,
+		&i.Street,
+		&i.City,
+		&i.PostCode,
+	)
+	return i, err
+}
+
+const getUserAddress = `-- name: GetUserAddress :one
+SELECT address_id, user_id, label, recipient_name, phone_number, street, city, post_code, is_default, created_at, updated_at FROM addresses
+WHERE address_id = $1 AND user_id = $2
+`
+
+type GetUserAddressParams struct {
+	AddressID int32       `json:"address_id"`
+	UserID    pgtype.UUID `json:"user_id"`
+}
+
+// PENTING: Dipakai Go untuk snapshot alamat pengiriman order
+func (q *Queries) GetUserAddress(ctx context.Context, arg GetUserAddressParams) (Address, error) {
+	row := q.db.QueryRow(ctx, getUserAddress, arg.AddressID, arg.UserID)
+	var i Address
+	err := row.Scan(
+		&i.AddressID,
+		&i.UserID,
+		&i.Label,
+		&i.RecipientName,
+		&i.PhoneNumber,
+		&i.Street,
+		&i.City,
+		&i.PostCode,
+		&i.IsDefault,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const listOrders = `-- name: ListOrders :many
 SELECT 
     o.order_id,
@@ -88,7 +205,12 @@ SELECT
     u.full_name AS customer_name,
     u.phone_number,
     p.product_name,
-    p.image_url
+    p.image_url,
+    o.ship_recipient_name,
+    o.ship_phone_number,
+    o.ship_street,
+    o.ship_city,
+    o.ship_post_code
 FROM orders o
 JOIN users u ON o.user_id = u.user_id
 JOIN products p ON o.product_id = p.product_id
@@ -96,15 +218,20 @@ ORDER BY o.order_date ASC
 `
 
 type ListOrdersRow struct {
-	OrderID      int32            `json:"order_id"`
-	OrderDate    pgtype.Timestamp `json:"order_date"`
-	TotalAmount  pgtype.Numeric   `json:"total_amount"`
-	Quantity     int32            `json:"quantity"`
-	Status       string           `json:"status"`
-	CustomerName string           `json:"customer_name"`
-	PhoneNumber  string           `json:"phone_number"`
-	ProductName  string           `json:"product_name"`
-	ImageUrl     string           `json:"image_url"`
+	OrderID           int32            `json:"order_id"`
+	OrderDate         pgtype.Timestamp `json:"order_date"`
+	TotalAmount       pgtype.Numeric   `json:"total_amount"`
+	Quantity          int32            `json:"quantity"`
+	Status            string           `json:"status"`
+	CustomerName      string           `json:"customer_name"`
+	PhoneNumber       string           `json:"phone_number"`
+	ProductName       string           `json:"product_name"`
+	ImageUrl          string           `json:"image_url"`
+	ShipRecipientName pgtype.Text      `json:"ship_recipient_name"`
+	ShipPhoneNumber   pgtype.Text      `json:"ship_phone_number"`
+	ShipStreet        pgtype.Text      `json:"ship_street"`
+	ShipCity          pgtype.Text      `json:"ship_city"`
+	ShipPostCode      pgtype.Text      `json:"ship_post_code"`
 }
 
 func (q *Queries) ListOrders(ctx context.Context) ([]ListOrdersRow, error) {
@@ -126,6 +253,11 @@ func (q *Queries) ListOrders(ctx context.Context) ([]ListOrdersRow, error) {
 			&i.PhoneNumber,
 			&i.ProductName,
 			&i.ImageUrl,
+			&i.ShipRecipientName,
+			&i.ShipPhoneNumber,
+			&i.ShipStreet,
+			&i.ShipCity,
+			&i.ShipPostCode,
 		); err != nil {
 			return nil, err
 		}
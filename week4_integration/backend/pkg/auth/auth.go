@@ -0,0 +1,78 @@
+// Package auth membungkus penyedia login (signup, sign-in, refresh token,
+// reset password, dan lookup/hapus user oleh admin) di belakang interface
+// Provider, supaya handler tidak bergantung langsung pada supabase-go.
+//
+// Supabase (NewSupabase) memanggil GoTrue seperti sebelumnya. Local (NewLocal)
+// menyimpan hash bcrypt di Store dan menandatangani JWT sendiri dengan secret
+// yang sama dengan yang dicek middleware, jadi backend bisa jalan tanpa
+// Supabase. MemoryStore cukup untuk test dan development satu proses;
+// PostgresStore dipakai mode standalone (AUTH_MODE=standalone) di atas schema
+// auth yang dibuat Bootstrap. New memilih provider sesuai konfigurasi.
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrInvalidToken        = errors.New("invalid or expired token")
+	ErrEmailTaken          = errors.New("email is already registered")
+	ErrUserNotFound        = errors.New("user not found")
+	// ErrEmailNotConfigured: provider tidak bisa mengirim email reset password.
+	ErrEmailNotConfigured = errors.New("email sender is not configured")
+)
+
+// User adalah akun login, bukan profil toko (tabel users).
+type User struct {
+	ID             string
+	Email          string
+	EmailConfirmed bool
+	// Metadata adalah data tambahan dari signup (raw_user_meta_data).
+	Metadata  map[string]any
+	CreatedAt time.Time
+}
+
+// Session adalah hasil login atau refresh.
+type Session struct {
+	AccessToken  string
+	RefreshToken string
+	TokenType    string
+	ExpiresIn    int
+	User         User
+}
+
+type SignUpParams struct {
+	Email    string
+	Password string
+	Data     map[string]any
+}
+
+type Provider interface {
+	SignUp(ctx context.Context, p SignUpParams) (User, error)
+	SignIn(ctx context.Context, email, password string) (Session, error)
+	// Refresh menukar refresh token dengan session baru. accessToken boleh
+	// kosong atau sudah expired.
+	Refresh(ctx context.Context, accessToken, refreshToken string) (Session, error)
+	SignOut(ctx context.Context, accessToken string) error
+
+	// SendPasswordReset dan ResendVerification tidak memberi tahu apakah
+	// email terdaftar; pemanggil sebaiknya mengabaikan error-nya, kecuali
+	// ErrEmailNotConfigured yang berlaku untuk semua email.
+	SendPasswordReset(ctx context.Context, email, redirectTo string) error
+	ResendVerification(ctx context.Context, email string) error
+	// VerifyRecovery menukar token_hash dari email reset dengan session.
+	VerifyRecovery(ctx context.Context, tokenHash string) (Session, error)
+	UpdatePassword(ctx context.Context, accessToken, password string) error
+
+	// Operasi admin. CreateUser langsung menandai email terkonfirmasi.
+	GetUser(ctx context.Context, id string) (User, error)
+	CreateUser(ctx context.Context, p SignUpParams) (User, error)
+	DeleteUser(ctx context.Context, id string) error
+
+	// Ping dipakai /readyz.
+	Ping(ctx context.Context) error
+}
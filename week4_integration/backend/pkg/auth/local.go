@@ -0,0 +1,314 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Jenis Token di Store.
+const (
+	TokenRefresh  = "refresh"
+	TokenRecovery = "recovery"
+)
+
+// Account adalah User beserta hash bcrypt password-nya.
+type Account struct {
+	User
+	PasswordHash []byte
+}
+
+// Token adalah refresh token atau token reset password. Store hanya
+// menyimpan hash SHA-256-nya, jadi bocornya tabel tidak membocorkan token.
+type Token struct {
+	Hash      string
+	Kind      string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+// Store menyimpan akun dan token untuk Local. Email dibandingkan tanpa
+// membedakan huruf besar/kecil.
+type Store interface {
+	// CreateAccount mengembalikan ErrEmailTaken kalau email sudah dipakai.
+	CreateAccount(ctx context.Context, a Account) error
+	// AccountByEmail dan AccountByID mengembalikan ErrUserNotFound.
+	AccountByEmail(ctx context.Context, email string) (Account, error)
+	AccountByID(ctx context.Context, id string) (Account, error)
+	// SetPassword ikut mencabut semua refresh token akun itu (dalam satu
+	// transaksi), jadi session yang sudah ada tidak bertahan setelah reset.
+	SetPassword(ctx context.Context, id string, hash []byte) error
+	// DeleteAccount ikut menghapus semua token akun itu.
+	DeleteAccount(ctx context.Context, id string) error
+
+	SaveToken(ctx context.Context, t Token) error
+	// TakeToken menghapus token dan mengembalikannya, jadi setiap token hanya
+	// bisa dipakai sekali. Token yang tidak ada atau expired: ErrInvalidToken.
+	TakeToken(ctx context.Context, kind, hash string, now time.Time) (Token, error)
+	DeleteTokens(ctx context.Context, userID, kind string) error
+}
+
+// Local mengelola password sendiri dan menerbitkan JWT HS256 dengan claim
+// yang sama seperti Supabase (sub, email, aud, role), jadi AuthRequired dan
+// AdminOnly tidak perlu tahu provider mana yang dipakai.
+//
+// Local tidak mengirim email verifikasi: akun langsung terkonfirmasi.
+type Local struct {
+	store  Store
+	secret []byte
+	now    func() time.Time
+
+	AccessTTL   time.Duration
+	RefreshTTL  time.Duration
+	RecoveryTTL time.Duration
+
+	// SendEmail mengirim token reset password ke user. Kalau nil,
+	// SendPasswordReset mengembalikan ErrEmailNotConfigured.
+	SendEmail func(ctx context.Context, to, kind, token, redirectTo string) error
+}
+
+func NewLocal(store Store, jwtSecret string) *Local {
+	return &Local{
+		store:       store,
+		secret:      []byte(jwtSecret),
+		now:         time.Now,
+		AccessTTL:   time.Hour,
+		RefreshTTL:  30 * 24 * time.Hour,
+		RecoveryTTL: time.Hour,
+	}
+}
+
+// dummyHash dibandingkan saat email tidak terdaftar, supaya waktu respons
+// login tidak membocorkan email mana yang ada.
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not-a-real-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40 // UUID v4
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func newToken() (raw, hash string) {
+	b := make([]byte, 32)
+	rand.Read(b)
+	raw = base64.RawURLEncoding.EncodeToString(b)
+	return raw, hashToken(raw)
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func (l *Local) SignUp(ctx context.Context, p SignUpParams) (User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(p.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, err
+	}
+
+	a := Account{
+		User: User{
+			ID:             newID(),
+			Email:          strings.ToLower(strings.TrimSpace(p.Email)),
+			EmailConfirmed: true,
+			Metadata:       maps.Clone(p.Data),
+			CreatedAt:      l.now(),
+		},
+		PasswordHash: hash,
+	}
+	if a.Metadata == nil {
+		a.Metadata = map[string]any{}
+	}
+	if err := l.store.CreateAccount(ctx, a); err != nil {
+		return User{}, err
+	}
+	return a.User, nil
+}
+
+func (l *Local) SignIn(ctx context.Context, email, password string) (Session, error) {
+	a, err := l.store.AccountByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+			return Session{}, ErrInvalidCredentials
+		}
+		return Session{}, err
+	}
+	if bcrypt.CompareHashAndPassword(a.PasswordHash, []byte(password)) != nil {
+		return Session{}, ErrInvalidCredentials
+	}
+	return l.newSession(ctx, a.User)
+}
+
+// Refresh memutar refresh token: token lama tidak bisa dipakai lagi.
+func (l *Local) Refresh(ctx context.Context, accessToken, refreshToken string) (Session, error) {
+	t, err := l.store.TakeToken(ctx, TokenRefresh, hashToken(refreshToken), l.now())
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			err = ErrInvalidRefreshToken
+		}
+		return Session{}, err
+	}
+
+	a, err := l.store.AccountByID(ctx, t.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			err = ErrInvalidRefreshToken
+		}
+		return Session{}, err
+	}
+	return l.newSession(ctx, a.User)
+}
+
+// SignOut mencabut semua refresh token user. Access token yang sudah terbit
+// tetap berlaku sampai expired, sama seperti Supabase.
+func (l *Local) SignOut(ctx context.Context, accessToken string) error {
+	userID, err := l.subject(accessToken)
+	if err != nil {
+		return err
+	}
+	return l.store.DeleteTokens(ctx, userID, TokenRefresh)
+}
+
+func (l *Local) SendPasswordReset(ctx context.Context, email, redirectTo string) error {
+	// Dicek sebelum lookup supaya responsnya sama untuk semua email
+	if l.SendEmail == nil {
+		return ErrEmailNotConfigured
+	}
+
+	a, err := l.store.AccountByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	raw, hash := newToken()
+	err = l.store.SaveToken(ctx, Token{Hash: hash, Kind: TokenRecovery, UserID: a.ID, ExpiresAt: l.now().Add(l.RecoveryTTL)})
+	if err != nil {
+		return err
+	}
+
+	return l.SendEmail(ctx, a.Email, TokenRecovery, raw, redirectTo)
+}
+
+// ResendVerification tidak melakukan apa-apa karena akun Local langsung
+// terkonfirmasi.
+func (l *Local) ResendVerification(ctx context.Context, email string) error {
+	return nil
+}
+
+func (l *Local) VerifyRecovery(ctx context.Context, tokenHash string) (Session, error) {
+	t, err := l.store.TakeToken(ctx, TokenRecovery, hashToken(tokenHash), l.now())
+	if err != nil {
+		return Session{}, err
+	}
+
+	a, err := l.store.AccountByID(ctx, t.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			err = ErrInvalidToken
+		}
+		return Session{}, err
+	}
+	return l.newSession(ctx, a.User)
+}
+
+func (l *Local) UpdatePassword(ctx context.Context, accessToken, password string) error {
+	userID, err := l.subject(accessToken)
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return l.store.SetPassword(ctx, userID, hash)
+}
+
+func (l *Local) GetUser(ctx context.Context, id string) (User, error) {
+	a, err := l.store.AccountByID(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	return a.User, nil
+}
+
+func (l *Local) CreateUser(ctx context.Context, p SignUpParams) (User, error) {
+	return l.SignUp(ctx, p)
+}
+
+func (l *Local) DeleteUser(ctx context.Context, id string) error {
+	return l.store.DeleteAccount(ctx, id)
+}
+
+func (l *Local) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (l *Local) newSession(ctx context.Context, u User) (Session, error) {
+	now := l.now()
+	access, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   u.ID,
+		"email": u.Email,
+		"aud":   "authenticated",
+		"role":  "authenticated",
+		"iat":   now.Unix(),
+		"exp":   now.Add(l.AccessTTL).Unix(),
+	}).SignedString(l.secret)
+	if err != nil {
+		return Session{}, err
+	}
+
+	raw, hash := newToken()
+	err = l.store.SaveToken(ctx, Token{Hash: hash, Kind: TokenRefresh, UserID: u.ID, ExpiresAt: now.Add(l.RefreshTTL)})
+	if err != nil {
+		return Session{}, err
+	}
+
+	return Session{
+		AccessToken:  access,
+		RefreshToken: raw,
+		TokenType:    "bearer",
+		ExpiresIn:    int(l.AccessTTL.Seconds()),
+		User:         u,
+	}, nil
+}
+
+// subject memvalidasi access token yang diterbitkan Local dan mengembalikan sub.
+func (l *Local) subject(accessToken string) (string, error) {
+	token, err := jwt.Parse(accessToken, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return l.secret, nil
+	})
+	if err != nil || !token.Valid {
+		return "", ErrInvalidToken
+	}
+
+	claims, _ := token.Claims.(jwt.MapClaims)
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return "", ErrInvalidToken
+	}
+	return sub, nil
+}
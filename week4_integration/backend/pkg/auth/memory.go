@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryStore menyimpan akun di memori proses. Hilang saat restart, jadi
+// hanya untuk test dan development.
+type MemoryStore struct {
+	mu       sync.Mutex
+	accounts map[string]Account
+	tokens   map[string]Token
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		accounts: make(map[string]Account),
+		tokens:   make(map[string]Token),
+	}
+}
+
+func (s *MemoryStore) CreateAccount(ctx context.Context, a Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.byEmail(a.Email); err == nil {
+		return ErrEmailTaken
+	}
+	s.accounts[a.ID] = a
+	return nil
+}
+
+func (s *MemoryStore) byEmail(email string) (Account, error) {
+	for _, a := range s.accounts {
+		if strings.EqualFold(a.Email, email) {
+			return a, nil
+		}
+	}
+	return Account{}, ErrUserNotFound
+}
+
+func (s *MemoryStore) AccountByEmail(ctx context.Context, email string) (Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.byEmail(email)
+}
+
+func (s *MemoryStore) AccountByID(ctx context.Context, id string) (Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.accounts[id]
+	if !ok {
+		return Account{}, ErrUserNotFound
+	}
+	return a, nil
+}
+
+func (s *MemoryStore) SetPassword(ctx context.Context, id string, hash []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.accounts[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+	a.PasswordHash = hash
+	s.accounts[id] = a
+	for hash, t := range s.tokens {
+		if t.UserID == id && t.Kind == TokenRefresh {
+			delete(s.tokens, hash)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) DeleteAccount(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.accounts[id]; !ok {
+		return ErrUserNotFound
+	}
+	delete(s.accounts, id)
+	for hash, t := range s.tokens {
+		if t.UserID == id {
+			delete(s.tokens, hash)
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) SaveToken(ctx context.Context, t Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[t.Hash] = t
+	return nil
+}
+
+func (s *MemoryStore) TakeToken(ctx context.Context, kind, hash string, now time.Time) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[hash]
+	if !ok || t.Kind != kind {
+		return Token{}, ErrInvalidToken
+	}
+	delete(s.tokens, hash)
+	if !now.Before(t.ExpiresAt) {
+		return Token{}, ErrInvalidToken
+	}
+	return t, nil
+}
+
+func (s *MemoryStore) DeleteTokens(ctx context.Context, userID, kind string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, t := range s.tokens {
+		if t.UserID == userID && t.Kind == kind {
+			delete(s.tokens, hash)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed standalone.sql
+var standaloneSchema string
+
+// Bootstrap membuat schema auth untuk mode standalone kalau belum ada. Harus
+// jalan sebelum migrasi karena tabel users mereferensikan auth.users.
+func Bootstrap(ctx context.Context, db *pgxpool.Pool) error {
+	_, err := db.Exec(ctx, standaloneSchema)
+	return err
+}
+
+// PostgresStore menyimpan akun di auth.users dan token di auth.tokens yang
+// dibuat Bootstrap.
+type PostgresStore struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresStore(db *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) CreateAccount(ctx context.Context, a Account) error {
+	var confirmedAt *time.Time
+	if a.EmailConfirmed {
+		confirmedAt = &a.CreatedAt
+	}
+
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO auth.users (id, email, encrypted_password, email_confirmed_at, raw_user_meta_data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		a.ID, a.Email, string(a.PasswordHash), confirmedAt, a.Metadata, a.CreatedAt)
+
+	// Unique violation dari trigger profil (username) dibiarkan apa adanya
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.SchemaName == "auth" {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+const selectAccount = `
+	SELECT id::text, email, COALESCE(encrypted_password, ''), email_confirmed_at IS NOT NULL, raw_user_meta_data, created_at
+	FROM auth.users`
+
+func (s *PostgresStore) account(ctx context.Context, where string, arg any) (Account, error) {
+	var a Account
+	var hash string
+	err := s.db.QueryRow(ctx, selectAccount+" WHERE "+where, arg).
+		Scan(&a.ID, &a.Email, &hash, &a.EmailConfirmed, &a.Metadata, &a.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Account{}, ErrUserNotFound
+	}
+	if err != nil {
+		return Account{}, err
+	}
+	a.PasswordHash = []byte(hash)
+	return a, nil
+}
+
+func (s *PostgresStore) AccountByEmail(ctx context.Context, email string) (Account, error) {
+	return s.account(ctx, "lower(email) = lower($1)", email)
+}
+
+// userUUID mengubah id jadi UUID. id dari token atau admin API belum tentu
+// valid, dan id yang tidak valid pasti tidak ada di auth.users.
+func userUUID(id string) (pgtype.UUID, error) {
+	var u pgtype.UUID
+	if err := u.Scan(id); err != nil {
+		return u, ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (s *PostgresStore) AccountByID(ctx context.Context, id string) (Account, error) {
+	uid, err := userUUID(id)
+	if err != nil {
+		return Account{}, err
+	}
+	return s.account(ctx, "id = $1", uid)
+}
+
+func (s *PostgresStore) SetPassword(ctx context.Context, id string, hash []byte) error {
+	uid, err := userUUID(id)
+	if err != nil {
+		return err
+	}
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE auth.users SET encrypted_password = $2 WHERE id = $1", uid, string(hash))
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		_, err = tx.Exec(ctx, "DELETE FROM auth.tokens WHERE user_id = $1 AND kind = $2", uid, TokenRefresh)
+		return err
+	})
+}
+
+// DeleteAccount juga menghapus token (ON DELETE CASCADE) dan profil users.
+func (s *PostgresStore) DeleteAccount(ctx context.Context, id string) error {
+	uid, err := userUUID(id)
+	if err != nil {
+		return err
+	}
+	tag, err := s.db.Exec(ctx, "DELETE FROM auth.users WHERE id = $1", uid)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *PostgresStore) SaveToken(ctx context.Context, t Token) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO auth.tokens (token_hash, kind, user_id, expires_at) VALUES ($1, $2, $3, $4)`,
+		t.Hash, t.Kind, t.UserID, t.ExpiresAt)
+	return err
+}
+
+// TakeToken menghapus dan membaca token dalam satu statement, jadi dua request
+// yang memakai refresh token yang sama tidak bisa sama-sama berhasil.
+func (s *PostgresStore) TakeToken(ctx context.Context, kind, hash string, now time.Time) (Token, error) {
+	var t Token
+	err := s.db.QueryRow(ctx, `
+		DELETE FROM auth.tokens WHERE token_hash = $1 AND kind = $2
+		RETURNING token_hash, kind, user_id::text, expires_at`, hash, kind).
+		Scan(&t.Hash, &t.Kind, &t.UserID, &t.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Token{}, ErrInvalidToken
+	}
+	if err != nil {
+		return Token{}, err
+	}
+	if !now.Before(t.ExpiresAt) {
+		return Token{}, ErrInvalidToken
+	}
+	return t, nil
+}
+
+func (s *PostgresStore) DeleteTokens(ctx context.Context, userID, kind string) error {
+	uid, err := userUUID(userID)
+	if err != nil {
+		return nil
+	}
+	// Sekalian membuang token expired milik siapa pun supaya tabel tidak terus tumbuh
+	_, err = s.db.Exec(ctx, `
+		DELETE FROM auth.tokens WHERE (user_id = $1 AND kind = $2) OR expires_at < NOW()`, uid, kind)
+	return err
+}
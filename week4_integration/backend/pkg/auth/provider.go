@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nedpals/supabase-go"
+
+	"backend/pkg/config"
+	"backend/pkg/tracing"
+)
+
+// New memilih Provider sesuai cfg.Auth.Mode. Mode standalone memakai
+// PostgresStore di db, jadi Bootstrap harus sudah dijalankan.
+func New(cfg *config.Config, db *pgxpool.Pool) Provider {
+	if cfg.Auth.Mode == config.AuthStandalone {
+		l := NewLocal(NewPostgresStore(db), cfg.Auth.JWTSecret)
+		l.AccessTTL = cfg.Auth.AccessTTL
+		l.RefreshTTL = cfg.Auth.RefreshTTL
+		return l
+	}
+
+	client := supabase.CreateClient(cfg.SupabaseURL, cfg.SupabaseKey)
+	tracing.InstrumentClient(client.HTTPClient, "supabase")
+	return NewSupabase(client, cfg.SupabaseKey)
+}
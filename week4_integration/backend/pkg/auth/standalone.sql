@@ -0,0 +1,53 @@
+-- Schema auth untuk mode standalone (AUTH_MODE=standalone): pengganti minimal
+-- auth.users milik Supabase supaya migrasi (users.user_id REFERENCES
+-- auth.users) tetap jalan di Postgres biasa. Idempotent, dijalankan Bootstrap
+-- sebelum migrasi.
+CREATE SCHEMA IF NOT EXISTS auth;
+
+CREATE TABLE IF NOT EXISTS auth.users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email TEXT UNIQUE,
+    encrypted_password TEXT,
+    email_confirmed_at TIMESTAMPTZ,
+    raw_user_meta_data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_idx ON auth.users (lower(email));
+
+-- Refresh token dan token reset password; hanya hash SHA-256 yang disimpan
+CREATE TABLE IF NOT EXISTS auth.tokens (
+    token_hash TEXT PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('refresh', 'recovery')),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS tokens_user_idx ON auth.tokens (user_id, kind);
+
+-- Sama seperti trigger di project Supabase: signup yang membawa username di
+-- metadata langsung mendapat profil di public.users. Kalau username sudah
+-- dipakai, signup ikut gagal.
+CREATE OR REPLACE FUNCTION auth.handle_new_user() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF NEW.raw_user_meta_data ? 'username' THEN
+        INSERT INTO public.users (user_id, username, full_name, phone_number, street, city, post_code)
+        VALUES (
+            NEW.id,
+            NEW.raw_user_meta_data->>'username',
+            COALESCE(NEW.raw_user_meta_data->>'full_name', ''),
+            COALESCE(NEW.raw_user_meta_data->>'phone_number', ''),
+            COALESCE(NEW.raw_user_meta_data->>'street', ''),
+            COALESCE(NEW.raw_user_meta_data->>'city', ''),
+            COALESCE(NEW.raw_user_meta_data->>'post_code', '')
+        );
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS on_auth_user_created ON auth.users;
+CREATE TRIGGER on_auth_user_created
+    AFTER INSERT ON auth.users
+    FOR EACH ROW EXECUTE FUNCTION auth.handle_new_user();
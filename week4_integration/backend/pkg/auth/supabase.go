@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/nedpals/supabase-go"
+)
+
+// Supabase memakai Supabase Auth (GoTrue). Operasi admin butuh key service
+// role; dengan anon key hanya operasi user yang berhasil.
+type Supabase struct {
+	client *supabase.Client
+	key    string
+}
+
+// NewSupabase membungkus client yang sudah ada. key harus sama dengan key
+// yang dipakai membuat client (supabase-go tidak mengekspornya).
+func NewSupabase(client *supabase.Client, key string) *Supabase {
+	return &Supabase{client: client, key: key}
+}
+
+func fromSupabaseUser(u supabase.User) User {
+	return User{
+		ID:             u.ID,
+		Email:          u.Email,
+		EmailConfirmed: !u.ConfirmedAt.IsZero(),
+		Metadata:       u.UserMetadata,
+		CreatedAt:      u.CreatedAt,
+	}
+}
+
+func fromAdminUser(u *supabase.AdminUser) User {
+	return User{
+		ID:             u.ID,
+		Email:          u.Email,
+		EmailConfirmed: u.EmailConfirmedAt != nil,
+		Metadata:       u.UserMetaData,
+		CreatedAt:      u.CreatedAt,
+	}
+}
+
+func fromDetails(d *supabase.AuthenticatedDetails) Session {
+	return Session{
+		AccessToken:  d.AccessToken,
+		RefreshToken: d.RefreshToken,
+		TokenType:    d.TokenType,
+		ExpiresIn:    d.ExpiresIn,
+		User:         fromSupabaseUser(d.User),
+	}
+}
+
+func (s *Supabase) SignUp(ctx context.Context, p SignUpParams) (User, error) {
+	u, err := s.client.Auth.SignUp(ctx, supabase.UserCredentials{
+		Email:    p.Email,
+		Password: p.Password,
+		Data:     p.Data,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	return fromSupabaseUser(*u), nil
+}
+
+func (s *Supabase) SignIn(ctx context.Context, email, password string) (Session, error) {
+	d, err := s.client.Auth.SignIn(ctx, supabase.UserCredentials{Email: email, Password: password})
+	if err != nil {
+		return Session{}, err
+	}
+	return fromDetails(d), nil
+}
+
+func (s *Supabase) Refresh(ctx context.Context, accessToken, refreshToken string) (Session, error) {
+	if accessToken == "" {
+		accessToken = s.key
+	}
+	d, err := s.client.Auth.RefreshUser(ctx, accessToken, refreshToken)
+	if err != nil {
+		return Session{}, err
+	}
+	return fromDetails(d), nil
+}
+
+func (s *Supabase) SignOut(ctx context.Context, accessToken string) error {
+	return s.client.Auth.SignOut(ctx, accessToken)
+}
+
+func (s *Supabase) SendPasswordReset(ctx context.Context, email, redirectTo string) error {
+	return s.client.Auth.ResetPasswordForEmail(ctx, email, redirectTo)
+}
+
+func (s *Supabase) ResendVerification(ctx context.Context, email string) error {
+	return s.do(ctx, http.MethodPost, supabase.AuthEndpoint+"/resend", "", map[string]string{
+		"type":  "signup",
+		"email": email,
+	}, nil)
+}
+
+func (s *Supabase) VerifyRecovery(ctx context.Context, tokenHash string) (Session, error) {
+	var d supabase.AuthenticatedDetails
+	err := s.do(ctx, http.MethodPost, supabase.AuthEndpoint+"/verify", "", map[string]string{
+		"type":       "recovery",
+		"token_hash": tokenHash,
+	}, &d)
+	if err != nil {
+		return Session{}, err
+	}
+	return fromDetails(&d), nil
+}
+
+func (s *Supabase) UpdatePassword(ctx context.Context, accessToken, password string) error {
+	_, err := s.client.Auth.UpdateUser(ctx, accessToken, map[string]interface{}{"password": password})
+	return err
+}
+
+func (s *Supabase) GetUser(ctx context.Context, id string) (User, error) {
+	u, err := s.client.Admin.GetUser(ctx, id)
+	if err != nil {
+		var errRes *supabase.ErrorResponse
+		if errors.As(err, &errRes) && errRes.Code == http.StatusNotFound {
+			err = ErrUserNotFound
+		}
+		return User{}, err
+	}
+	return fromAdminUser(u), nil
+}
+
+func (s *Supabase) CreateUser(ctx context.Context, p SignUpParams) (User, error) {
+	u, err := s.client.Admin.CreateUser(ctx, supabase.AdminUserParams{
+		Email:        p.Email,
+		Password:     &p.Password,
+		EmailConfirm: true,
+		UserMetadata: p.Data,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	return fromAdminUser(u), nil
+}
+
+// DeleteUser belum ada di supabase-go, jadi memanggil admin API langsung.
+func (s *Supabase) DeleteUser(ctx context.Context, id string) error {
+	err := s.do(ctx, http.MethodDelete, supabase.AdminEndpoint+"/users/"+id, s.key, nil, nil)
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.status == http.StatusNotFound {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+func (s *Supabase) Ping(ctx context.Context) error {
+	return s.do(ctx, http.MethodGet, supabase.AuthEndpoint+"/health", "", nil, nil)
+}
+
+// do memanggil endpoint GoTrue yang belum dibungkus supabase-go lewat base URL
+// dan HTTP client yang sama (termasuk instrumentasi tracing-nya).
+func (s *Supabase) do(ctx context.Context, method, path, bearer string, body, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", s.client.BaseURL, path), reqBody)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("apikey", s.key)
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	res, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		var errRes struct {
+			Message string `json:"msg"`
+		}
+		json.NewDecoder(res.Body).Decode(&errRes)
+		return &statusError{path: path, status: res.StatusCode, message: errRes.Message}
+	}
+
+	if out != nil {
+		return json.NewDecoder(res.Body).Decode(out)
+	}
+	return nil
+}
+
+type statusError struct {
+	path    string
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("supabase %s: status %d: %s", e.path, e.status, e.message)
+}
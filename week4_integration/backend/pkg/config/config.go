@@ -0,0 +1,479 @@
+// Package config memuat dan memvalidasi konfigurasi backend saat startup,
+// supaya variabel yang hilang/salah format gagal di awal, bukan saat request.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+
+	"backend/pkg/i18n"
+)
+
+const (
+	EnvDevelopment = "development"
+	EnvStaging     = "staging"
+	EnvProduction  = "production"
+)
+
+type CORS struct {
+	AllowedOrigins      []string
+	AdminAllowedOrigins []string
+	AllowCredentials    bool
+	MaxAge              time.Duration
+}
+
+type HTTP struct {
+	Port              int
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+	ShutdownTimeout   time.Duration
+}
+
+// Database berisi tuning pgxpool. StatementCacheMode perlu "exec" atau
+// "simple_protocol" kalau lewat PgBouncer/Supavisor mode transaction.
+type Database struct {
+	MaxConns           int32
+	MinConns           int32
+	MaxConnLifetime    time.Duration
+	MaxConnIdleTime    time.Duration
+	HealthCheckPeriod  time.Duration
+	StatementCacheMode string
+	ConnectTimeout     time.Duration
+	ConnectRetries     int
+	ConnectBackoff     time.Duration
+
+	// MigrateOnStart menjalankan "migrate up" sebelum server lokal menerima
+	// request. Tidak berlaku di Vercel.
+	MigrateOnStart bool
+}
+
+// Log mengatur slog. Format "json" untuk staging/production, "text" lebih
+// enak dibaca saat development.
+type Log struct {
+	Level  slog.Level
+	Format string
+}
+
+// Metrics mengatur endpoint /metrics. Endpoint hanya dipasang kalau Enabled,
+// dan scraper harus mengirim "Authorization: Bearer <Token>".
+type Metrics struct {
+	Enabled bool
+	Token   string
+}
+
+// Tracing mengatur OpenTelemetry. Exporter "none" tetap memasang propagator
+// supaya trace context dari client diteruskan, tapi span tidak dikirim.
+type Tracing struct {
+	Exporter     string
+	OTLPEndpoint string
+	OTLPHeaders  map[string]string
+	SampleRatio  float64
+	ServiceName  string
+}
+
+// Health mengatur pengecekan /readyz.
+type Health struct {
+	CheckSupabase bool
+	Timeout       time.Duration
+}
+
+// Mode autentikasi untuk AUTH_MODE.
+const (
+	AuthSupabase   = "supabase"
+	AuthStandalone = "standalone"
+)
+
+// Auth memilih penyedia login. Mode "supabase" memakai GoTrue dan memverifikasi
+// token dengan SUPABASE_JWT_SECRET; mode "standalone" menyimpan akun di schema
+// auth database sendiri dan menerbitkan token dengan AUTH_JWT_SECRET.
+// JWTSecret selalu terisi sesuai mode, jadi middleware cukup membaca field ini.
+type Auth struct {
+	Mode      string
+	JWTSecret string
+
+	// AccessTTL dan RefreshTTL hanya dipakai mode standalone; di mode
+	// supabase umur token diatur di project Supabase.
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+// StatementCacheModes adalah nilai yang valid untuk DB_STATEMENT_CACHE_MODE.
+var StatementCacheModes = []string{"cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol"}
+
+type Config struct {
+	Env string
+
+	HTTP    HTTP
+	DB      Database
+	Health  Health
+	Auth    Auth
+	Log     Log
+	Metrics Metrics
+	Tracing Tracing
+
+	// DefaultLanguage dipakai kalau Accept-Language kosong atau tidak didukung.
+	DefaultLanguage string
+
+	DatabaseURL       string
+	SupabaseURL       string
+	SupabaseKey       string
+	SupabaseJWTSecret string
+
+	CORS CORS
+}
+
+// ValidationError berisi semua masalah konfigurasi sekaligus.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// source mencari nilai dengan prioritas: environment proses, CONFIG_FILE,
+// base.<APP_ENV>.env, lalu base.env. File tidak mengubah os.Environ.
+type source struct {
+	files []map[string]string
+}
+
+func (s source) lookup(key string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	for _, f := range s.files {
+		if v, ok := f[key]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+func readEnvFile(path string, required bool) (map[string]string, error) {
+	values, err := godotenv.Read(path)
+	if err != nil {
+		if !required && errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Load membaca konfigurasi dari environment dan file .env di working directory.
+func Load() (*Config, error) {
+	base, err := readEnvFile("base.env", false)
+	if err != nil {
+		return nil, err
+	}
+
+	src := source{files: []map[string]string{base}}
+
+	env := src.lookup("APP_ENV")
+	if env == "" {
+		env = EnvDevelopment
+	}
+
+	profile, err := readEnvFile("base."+env+".env", false)
+	if err != nil {
+		return nil, err
+	}
+	src.files = []map[string]string{profile, base}
+
+	if path := src.lookup("CONFIG_FILE"); path != "" {
+		file, err := readEnvFile(path, true)
+		if err != nil {
+			return nil, err
+		}
+		src.files = []map[string]string{file, profile, base}
+	}
+
+	return parse(src, env)
+}
+
+func parse(src source, env string) (*Config, error) {
+	var problems []string
+	problem := func(key, format string, args ...any) {
+		problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+	}
+	required := func(key string) string {
+		v := strings.TrimSpace(src.lookup(key))
+		if v == "" {
+			problem(key, "required")
+		}
+		return v
+	}
+
+	duration := func(key string, def time.Duration) time.Duration {
+		v := src.lookup(key)
+		if v == "" {
+			return def
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			problem(key, "must be a positive duration like 15s or 1m (got %q)", v)
+			return def
+		}
+		return d
+	}
+	integer := func(key string, def, lo, hi int) int {
+		v := src.lookup(key)
+		if v == "" {
+			return def
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < lo || n > hi {
+			problem(key, "must be a number between %d and %d (got %q)", lo, hi, v)
+			return def
+		}
+		return n
+	}
+
+	cfg := &Config{Env: env}
+
+	cfg.HTTP = HTTP{
+		Port:              integer("PORT", 8080, 1, 65535),
+		ReadTimeout:       duration("HTTP_READ_TIMEOUT", 15*time.Second),
+		ReadHeaderTimeout: duration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      duration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+		IdleTimeout:       duration("HTTP_IDLE_TIMEOUT", 60*time.Second),
+		MaxHeaderBytes:    integer("HTTP_MAX_HEADER_BYTES", 1<<20, 1<<10, 16<<20),
+		ShutdownTimeout:   duration("HTTP_SHUTDOWN_TIMEOUT", 20*time.Second),
+	}
+
+	switch env {
+	case EnvDevelopment, EnvStaging, EnvProduction:
+	default:
+		problem("APP_ENV", "must be one of %s, %s, %s (got %q)", EnvDevelopment, EnvStaging, EnvProduction, env)
+	}
+
+	cfg.DatabaseURL = required("DATABASE_URL")
+	if cfg.DatabaseURL != "" {
+		if _, err := pgxpool.ParseConfig(cfg.DatabaseURL); err != nil {
+			problem("DATABASE_URL", "not a valid Postgres connection string")
+		}
+	}
+
+	cfg.DB = Database{
+		MaxConns:           int32(integer("DB_MAX_CONNS", 10, 1, 1000)),
+		MinConns:           int32(integer("DB_MIN_CONNS", 0, 0, 1000)),
+		MaxConnLifetime:    duration("DB_MAX_CONN_LIFETIME", time.Hour),
+		MaxConnIdleTime:    duration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute),
+		HealthCheckPeriod:  duration("DB_HEALTH_CHECK_PERIOD", time.Minute),
+		StatementCacheMode: src.lookup("DB_STATEMENT_CACHE_MODE"),
+		ConnectTimeout:     duration("DB_CONNECT_TIMEOUT", 5*time.Second),
+		ConnectRetries:     integer("DB_CONNECT_RETRIES", 3, 1, 20),
+		ConnectBackoff:     duration("DB_CONNECT_BACKOFF", 200*time.Millisecond),
+	}
+	if v := src.lookup("MIGRATE_ON_START"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			problem("MIGRATE_ON_START", "must be true or false")
+		}
+		cfg.DB.MigrateOnStart = b
+	}
+	if cfg.DB.MinConns > cfg.DB.MaxConns {
+		problem("DB_MIN_CONNS", "must not exceed DB_MAX_CONNS (%d)", cfg.DB.MaxConns)
+	}
+	if cfg.DB.StatementCacheMode == "" {
+		cfg.DB.StatementCacheMode = "cache_statement"
+	}
+	if !slices.Contains(StatementCacheModes, cfg.DB.StatementCacheMode) {
+		problem("DB_STATEMENT_CACHE_MODE", "must be one of %s", strings.Join(StatementCacheModes, ", "))
+	}
+
+	cfg.Log.Format = src.lookup("LOG_FORMAT")
+	if cfg.Log.Format == "" {
+		cfg.Log.Format = "json"
+		if env == EnvDevelopment {
+			cfg.Log.Format = "text"
+		}
+	}
+	if cfg.Log.Format != "json" && cfg.Log.Format != "text" {
+		problem("LOG_FORMAT", "must be json or text (got %q)", cfg.Log.Format)
+	}
+	if v := src.lookup("LOG_LEVEL"); v != "" {
+		if err := cfg.Log.Level.UnmarshalText([]byte(v)); err != nil {
+			problem("LOG_LEVEL", "must be debug, info, warn or error (got %q)", v)
+		}
+	}
+
+	if v := src.lookup("METRICS_ENABLED"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			problem("METRICS_ENABLED", "must be true or false")
+		}
+		cfg.Metrics.Enabled = b
+	}
+	if cfg.Metrics.Enabled {
+		cfg.Metrics.Token = required("METRICS_TOKEN")
+		if cfg.Metrics.Token != "" && len(cfg.Metrics.Token) < 16 {
+			problem("METRICS_TOKEN", "must be at least 16 characters")
+		}
+	}
+
+	cfg.Tracing.Exporter = src.lookup("TRACING_EXPORTER")
+	switch cfg.Tracing.Exporter {
+	case "":
+		cfg.Tracing.Exporter = "none"
+	case "none", "stdout":
+	case "otlp":
+		cfg.Tracing.OTLPEndpoint = required("TRACING_OTLP_ENDPOINT")
+	default:
+		problem("TRACING_EXPORTER", "must be none, stdout or otlp (got %q)", cfg.Tracing.Exporter)
+	}
+	cfg.Tracing.OTLPHeaders = map[string]string{}
+	for _, pair := range splitList(src.lookup("TRACING_OTLP_HEADERS")) {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			problem("TRACING_OTLP_HEADERS", "must be a list of key=value (got %q)", pair)
+			continue
+		}
+		cfg.Tracing.OTLPHeaders[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	cfg.Tracing.SampleRatio = 1
+	if v := src.lookup("TRACING_SAMPLE_RATIO"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > 1 {
+			problem("TRACING_SAMPLE_RATIO", "must be a number between 0 and 1 (got %q)", v)
+		}
+		cfg.Tracing.SampleRatio = f
+	}
+	cfg.Tracing.ServiceName = src.lookup("TRACING_SERVICE_NAME")
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = "astar-backend"
+	}
+
+	cfg.DefaultLanguage = src.lookup("DEFAULT_LANGUAGE")
+	if cfg.DefaultLanguage == "" {
+		cfg.DefaultLanguage = "en"
+	}
+	if !slices.Contains(i18n.Supported(), cfg.DefaultLanguage) {
+		problem("DEFAULT_LANGUAGE", "must be one of %s (got %q)", strings.Join(i18n.Supported(), ", "), cfg.DefaultLanguage)
+	}
+
+	cfg.Health.Timeout = duration("HEALTH_CHECK_TIMEOUT", 2*time.Second)
+	if v := src.lookup("HEALTH_CHECK_SUPABASE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			problem("HEALTH_CHECK_SUPABASE", "must be true or false")
+		}
+		cfg.Health.CheckSupabase = b
+	}
+
+	cfg.Auth.Mode = src.lookup("AUTH_MODE")
+	if cfg.Auth.Mode == "" {
+		cfg.Auth.Mode = AuthSupabase
+	}
+	switch cfg.Auth.Mode {
+	case AuthSupabase:
+		cfg.SupabaseURL = strings.TrimRight(required("SUPABASE_URL"), "/")
+		if cfg.SupabaseURL != "" {
+			u, err := url.Parse(cfg.SupabaseURL)
+			switch {
+			case err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https"):
+				problem("SUPABASE_URL", "must be an http(s) URL")
+			case env == EnvProduction && u.Scheme != "https":
+				problem("SUPABASE_URL", "must use https in production")
+			}
+		}
+
+		cfg.SupabaseKey = required("SUPABASE_KEY")
+
+		cfg.SupabaseJWTSecret = required("SUPABASE_JWT_SECRET")
+		if cfg.SupabaseJWTSecret != "" && len(cfg.SupabaseJWTSecret) < 32 {
+			problem("SUPABASE_JWT_SECRET", "must be at least 32 characters")
+		}
+		cfg.Auth.JWTSecret = cfg.SupabaseJWTSecret
+	case AuthStandalone:
+		cfg.Auth.JWTSecret = required("AUTH_JWT_SECRET")
+		if cfg.Auth.JWTSecret != "" && len(cfg.Auth.JWTSecret) < 32 {
+			problem("AUTH_JWT_SECRET", "must be at least 32 characters")
+		}
+		cfg.Auth.AccessTTL = duration("AUTH_ACCESS_TOKEN_TTL", time.Hour)
+		cfg.Auth.RefreshTTL = duration("AUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour)
+		if cfg.Health.CheckSupabase {
+			problem("HEALTH_CHECK_SUPABASE", "cannot be enabled with AUTH_MODE=standalone")
+		}
+	default:
+		problem("AUTH_MODE", "must be %s or %s (got %q)", AuthSupabase, AuthStandalone, cfg.Auth.Mode)
+	}
+
+	cfg.CORS.AllowedOrigins = splitList(src.lookup("CORS_ALLOWED_ORIGINS"))
+	if len(cfg.CORS.AllowedOrigins) == 0 {
+		cfg.CORS.AllowedOrigins = []string{"*"}
+	}
+	cfg.CORS.AdminAllowedOrigins = splitList(src.lookup("CORS_ADMIN_ALLOWED_ORIGINS"))
+	checkOrigins := func(key string, origins []string) {
+		for _, origin := range origins {
+			if !validOrigin(origin) {
+				problem(key, "%q is not \"*\" or an origin like https://example.com", origin)
+			}
+		}
+	}
+	checkOrigins("CORS_ALLOWED_ORIGINS", cfg.CORS.AllowedOrigins)
+	checkOrigins("CORS_ADMIN_ALLOWED_ORIGINS", cfg.CORS.AdminAllowedOrigins)
+
+	if v := src.lookup("CORS_ALLOW_CREDENTIALS"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			problem("CORS_ALLOW_CREDENTIALS", "must be true or false")
+		}
+		cfg.CORS.AllowCredentials = b
+	}
+	// Browser menolak ACAO "*" untuk request dengan credentials, jadi
+	// kombinasi ini (termasuk default "*") hanya membuat semua origin ditolak
+	if cfg.CORS.AllowCredentials {
+		if slices.Contains(cfg.CORS.AllowedOrigins, "*") {
+			problem("CORS_ALLOWED_ORIGINS", "cannot be \"*\" (the default) when CORS_ALLOW_CREDENTIALS=true; list the origins explicitly")
+		}
+		if slices.Contains(cfg.CORS.AdminAllowedOrigins, "*") {
+			problem("CORS_ADMIN_ALLOWED_ORIGINS", "cannot be \"*\" when CORS_ALLOW_CREDENTIALS=true; list the origins explicitly")
+		}
+	}
+
+	cfg.CORS.MaxAge = 600 * time.Second
+	if v := src.lookup("CORS_MAX_AGE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			problem("CORS_MAX_AGE", "must be a non-negative number of seconds")
+		}
+		cfg.CORS.MaxAge = time.Duration(n) * time.Second
+	}
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.Path == ""
+}
@@ -0,0 +1,169 @@
+// Package database membuat pgxpool dari konfigurasi, dengan retry saat connect
+// supaya cold start serverless tidak gagal permanen karena gangguan sesaat.
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"backend/pkg/config"
+	"backend/pkg/logging"
+	"backend/pkg/tracing"
+)
+
+// maxCooldown membatasi jeda antar percobaan Lazy.Get setelah gagal berkali-kali.
+const maxCooldown = 30 * time.Second
+
+// maxConnectWait membatasi satu Connect di Lazy.Get, termasuk semua retry-nya.
+const maxConnectWait = 30 * time.Second
+
+var execModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// PoolConfig menerapkan tuning dari config.Database ke DATABASE_URL.
+func PoolConfig(c *config.Config) (*pgxpool.Config, error) {
+	pc, err := pgxpool.ParseConfig(c.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse DATABASE_URL: %w", err)
+	}
+
+	pc.MaxConns = c.DB.MaxConns
+	pc.MinConns = c.DB.MinConns
+	pc.MaxConnLifetime = c.DB.MaxConnLifetime
+	pc.MaxConnIdleTime = c.DB.MaxConnIdleTime
+	pc.HealthCheckPeriod = c.DB.HealthCheckPeriod
+	pc.ConnConfig.ConnectTimeout = c.DB.ConnectTimeout
+
+	mode, ok := execModes[c.DB.StatementCacheMode]
+	if !ok {
+		return nil, fmt.Errorf("unknown statement cache mode %q", c.DB.StatementCacheMode)
+	}
+	pc.ConnConfig.DefaultQueryExecMode = mode
+	pc.ConnConfig.Tracer = multitracer.New(logging.QueryTracer{}, tracing.QueryTracer{})
+	return pc, nil
+}
+
+// Connect membuat pool lalu Ping, diulang sampai DB_CONNECT_RETRIES kali dengan
+// backoff eksponensial. pgxpool.New sendiri tidak membuka koneksi, jadi tanpa
+// Ping kegagalan baru kelihatan di request pertama.
+func Connect(ctx context.Context, c *config.Config) (*pgxpool.Pool, error) {
+	pc, err := PoolConfig(c)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff := c.DB.ConnectBackoff
+	for attempt := 1; ; attempt++ {
+		pool, err := connectOnce(ctx, pc, c.DB.ConnectTimeout)
+		if err == nil {
+			return pool, nil
+		}
+		if attempt >= c.DB.ConnectRetries {
+			return nil, fmt.Errorf("connect database after %d attempts: %w", attempt, err)
+		}
+
+		// Jitter supaya instance yang cold start bersamaan tidak menyerbu barengan
+		wait := backoff/2 + rand.N(backoff/2+1)
+		slog.WarnContext(ctx, "db connect failed, retrying", "attempt", attempt, "retry_in", wait, "err", err)
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("connect database: %w", ctx.Err())
+		}
+		backoff *= 2
+	}
+}
+
+func connectOnce(ctx context.Context, pc *pgxpool.Config, timeout time.Duration) (*pgxpool.Pool, error) {
+	pool, err := pgxpool.NewWithConfig(ctx, pc.Copy())
+	if err != nil {
+		return nil, err
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := pool.Ping(pingCtx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Lazy membuat pool saat pertama kali dibutuhkan. Beda dengan sync.Once,
+// kegagalan tidak di-cache selamanya: setelah cooldown, Get mencoba lagi.
+// Aman dipakai dari banyak goroutine; hanya satu yang connect pada satu waktu.
+type Lazy struct {
+	cfg *config.Config
+
+	mu       sync.Mutex
+	pool     *pgxpool.Pool
+	err      error
+	failures int
+	retryAt  time.Time
+}
+
+func NewLazy(c *config.Config) *Lazy {
+	return &Lazy{cfg: c}
+}
+
+// Get mengembalikan pool yang sudah ada, atau mencoba connect. Selama cooldown
+// setelah gagal, error terakhir langsung dikembalikan tanpa menyentuh database.
+//
+// Connect tidak memakai cancel dari ctx: request lain mengantre di belakangnya,
+// jadi client pertama yang putus tidak boleh menggagalkan semuanya.
+func (l *Lazy) Get(ctx context.Context) (*pgxpool.Pool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.pool != nil {
+		return l.pool, nil
+	}
+	if l.err != nil && time.Now().Before(l.retryAt) {
+		return nil, l.err
+	}
+
+	connectCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), maxConnectWait)
+	defer cancel()
+	pool, err := Connect(connectCtx, l.cfg)
+	if err != nil {
+		l.failures++
+		cooldown := min(l.cfg.DB.ConnectBackoff<<min(l.failures, 16), maxCooldown)
+		l.err, l.retryAt = err, time.Now().Add(cooldown)
+		slog.ErrorContext(ctx, "db unavailable", "next_attempt_in", cooldown, "err", err)
+		return nil, err
+	}
+
+	l.pool, l.err, l.failures = pool, nil, 0
+	return pool, nil
+}
+
+// RetryAfter adalah sisa cooldown sebelum Get mencoba connect lagi.
+func (l *Lazy) RetryAfter() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return max(time.Until(l.retryAt), 0)
+}
+
+// Close menutup pool kalau sudah pernah dibuat.
+func (l *Lazy) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.pool != nil {
+		l.pool.Close()
+		l.pool = nil
+	}
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type claimsKey struct{}
+
+// WithClaims menyimpan claim JWT request di context supaya Scoped bisa
+// meneruskannya ke Postgres. Middleware auth memanggilnya setelah token valid.
+func WithClaims(ctx context.Context, claims map[string]any) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// Scoped menjalankan fn dalam transaksi dengan role dan claim request, sama
+// seperti PostgREST, jadi policy RLS (migrasi 0005) juga berlaku untuk query
+// backend. Request dengan claim "sub" memakai role authenticated, sisanya anon.
+//
+// set_config(..., true) setara SET LOCAL: hilang saat transaksi selesai, jadi
+// aman untuk koneksi pool dan PgBouncer mode transaction.
+func Scoped(ctx context.Context, db *pgxpool.Pool, fn func(tx pgx.Tx) error) error {
+	claims, _ := ctx.Value(claimsKey{}).(map[string]any)
+	role := "anon"
+	if sub, _ := claims["sub"].(string); sub != "" {
+		role = "authenticated"
+	}
+	if claims == nil {
+		claims = map[string]any{"role": role}
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+
+	return pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT set_config('role', $1, true), set_config('request.jwt.claims', $2, true)", role, string(claimsJSON))
+		if err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"backend/pkg/apierror"
+	"backend/pkg/service"
+)
+
+// auditTime membaca parameter waktu RFC 3339; kosong berarti tanpa batas.
+func auditTime(r *http.Request, field string) (time.Time, error) {
+	v := r.URL.Query().Get(field)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, apierror.Validation(apierror.FieldError{
+			Field:   field,
+			Code:    "datetime",
+			Message: "must be an RFC 3339 time like 2025-01-31T00:00:00Z",
+		})
+	}
+	return t, nil
+}
+
+// Mobile: audit log
+
+// HandleListAuditLog mendukung filter ?actor_id=&action=&entity_type=&entity_id=
+// dan rentang ?from=&to= (to eksklusif), terbaru dulu.
+func (h *HttpServer) HandleListAuditLog(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	actorID := q.Get("actor_id")
+	if actorID != "" {
+		var id pgtype.UUID
+		if err := id.Scan(actorID); err != nil {
+			writeError(w, r, apierror.Validation(apierror.FieldError{Field: "actor_id", Code: "uuid", Message: "must be a valid UUID"}))
+			return
+		}
+	}
+	since, err := auditTime(r, "from")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	until, err := auditTime(r, "to")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	page, pageSize := pageParams(r)
+
+	entries, total, err := h.Audit.List(r.Context(), service.AuditQuery{
+		ActorID:    actorID,
+		Action:     q.Get("action"),
+		EntityType: q.Get("entity_type"),
+		EntityID:   q.Get("entity_id"),
+		Since:      since,
+		Until:      until,
+		Page:       page,
+		PageSize:   pageSize,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if entries == nil {
+		entries = []service.AuditEntry{}
+	}
+
+	writePage(w, entries, page, pageSize, total)
+}
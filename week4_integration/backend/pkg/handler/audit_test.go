@@ -0,0 +1,89 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type auditEntry struct {
+	ActorID    string         `json:"actor_id"`
+	Action     string         `json:"action"`
+	EntityType string         `json:"entity_type"`
+	EntityID   string         `json:"entity_id"`
+	Before     map[string]any `json:"before"`
+	After      map[string]any `json:"after"`
+	RequestID  string         `json:"request_id"`
+	IP         string         `json:"ip"`
+}
+
+type auditPage struct {
+	Data  []auditEntry `json:"data"`
+	Total int64        `json:"total"`
+}
+
+func TestAuditLog(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	budi := e.createUser("budi", userOpts{})
+
+	var created struct {
+		ProductID int32 `json:"product_id"`
+	}
+	body := map[string]any{"name": "Hoodie", "category": "Hoodie", "price": 150000, "stock": 1}
+	e.send(http.MethodPost, "/api/admin/products", admin.Token, body).expect(http.StatusOK, &created)
+	body["price"] = 175000
+	e.send(http.MethodPut, fmt.Sprintf("/api/admin/products/%d", created.ProductID), admin.Token, body).expect(http.StatusOK, nil)
+
+	first := e.createOrder(budi.ID, created.ProductID, 1, "process")
+	second := e.createOrder(budi.ID, created.ProductID, 1, "process")
+	status := func(id int32) *response {
+		return e.send(http.MethodPut, fmt.Sprintf("/api/admin/orders/%d/status", id), admin.Token, map[string]string{"status": "done"})
+	}
+	status(first).expect(http.StatusOK, nil)
+
+	// Perubahan yang batal ikut membatalkan audit log-nya
+	status(second).expectError(http.StatusConflict, "insufficient_stock")
+	e.send(http.MethodDelete, fmt.Sprintf("/api/admin/products/%d", created.ProductID), admin.Token, nil).
+		expectError(http.StatusConflict, "resource_in_use")
+
+	e.send(http.MethodPut, "/api/admin/customers/"+budi.ID+"/role", admin.Token, map[string]string{"role": "admin"}).
+		expect(http.StatusOK, nil)
+	// Role yang sama tidak mencatat apa pun
+	e.send(http.MethodPut, "/api/admin/customers/"+budi.ID+"/role", admin.Token, map[string]string{"role": "admin"}).
+		expect(http.StatusOK, nil)
+
+	if n := e.queryInt("SELECT COUNT(*) FROM audit_log"); n != 4 {
+		t.Fatalf("audit rows = %d, want 4", n)
+	}
+
+	var page auditPage
+	e.get("/api/admin/audit?page_size=2", admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 4 || len(page.Data) != 2 {
+		t.Fatalf("audit page = %+v, want 2 of 4", page)
+	}
+	role := page.Data[0]
+	if role.Action != "user.role" || role.EntityID != budi.ID || role.Before["role"] != "user" || role.After["role"] != "admin" {
+		t.Errorf("role entry = %+v", role)
+	}
+	if role.ActorID != admin.ID || role.RequestID == "" || role.IP == "" {
+		t.Errorf("role entry actor = %q request = %q ip = %q", role.ActorID, role.RequestID, role.IP)
+	}
+	if order := page.Data[1]; order.Action != "order.status" || order.Before["status"] != "process" || order.After["status"] != "done" {
+		t.Errorf("order entry = %+v", order)
+	}
+
+	productID := fmt.Sprint(created.ProductID)
+	e.get("/api/admin/audit?entity_type=product&entity_id="+productID, admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 2 {
+		t.Fatalf("product entries = %d, want 2", page.Total)
+	}
+	if update := page.Data[0]; len(update.After) != 1 || update.After["unit_price"] == nil {
+		t.Errorf("update diff = %v -> %v, want only unit_price", update.Before, update.After)
+	}
+
+	// Audit log juga dijaga RLS: user biasa tidak bisa membacanya
+	if n := scopedInt(t, e.createUser("sari", userOpts{}).ID, "SELECT COUNT(*) FROM audit_log"); n != 0 {
+		t.Errorf("non-admin sees %d audit rows", n)
+	}
+}
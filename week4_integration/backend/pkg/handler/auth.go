@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"backend/pkg/apierror"
+	"backend/pkg/auth"
+	"backend/pkg/database"
+	"backend/pkg/i18n"
+	"backend/pkg/logging"
+)
+
+// sessionResponse adalah payload yang sama untuk login dan refresh,
+// supaya web dan mobile cukup punya satu parser.
+type sessionResponse struct {
+	AccessToken  string       `json:"access_token"`
+	RefreshToken string       `json:"refresh_token"`
+	TokenType    string       `json:"token_type"`
+	ExpiresIn    int          `json:"expires_in"`
+	ExpiresAt    int64        `json:"expires_at"`
+	User         userResponse `json:"user"`
+}
+
+type userResponse struct {
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	Username    string `json:"username"`
+	FullName    string `json:"full_name"`
+	PhoneNumber string `json:"phone_number"`
+	Role        string `json:"role"`
+	Street      string `json:"street"`
+	City        string `json:"city"`
+	PostCode    string `json:"post_code"`
+}
+
+func (h *HttpServer) loadUser(r *http.Request, userID, email string) (userResponse, error) {
+	var userUUID pgtype.UUID
+	if err := userUUID.Scan(userID); err != nil {
+		return userResponse{}, errUnauthorized.Wrap(err)
+	}
+
+	profile, err := h.Users.Profile(r.Context(), userID)
+	if err != nil {
+		return userResponse{}, err
+	}
+
+	return userResponse{
+		ID:          userID,
+		Email:       email,
+		Username:    profile.Username,
+		FullName:    profile.FullName,
+		PhoneNumber: profile.PhoneNumber,
+		Role:        profile.Role,
+		Street:      profile.Street,
+		City:        profile.City,
+		PostCode:    profile.PostCode,
+	}, nil
+}
+
+func (h *HttpServer) writeSession(w http.ResponseWriter, r *http.Request, session auth.Session) {
+	// Login dan refresh tidak lewat AuthRequired, jadi profil dibaca (lewat
+	// RLS) sebagai user dari session yang baru terbit
+	r = r.WithContext(database.WithClaims(r.Context(), map[string]any{
+		"sub":   session.User.ID,
+		"email": session.User.Email,
+		"role":  "authenticated",
+	}))
+	user, err := h.loadUser(r, session.User.ID, session.User.Email)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, sessionResponse{
+		AccessToken:  session.AccessToken,
+		RefreshToken: session.RefreshToken,
+		TokenType:    session.TokenType,
+		ExpiresIn:    session.ExpiresIn,
+		ExpiresAt:    time.Now().Add(time.Duration(session.ExpiresIn) * time.Second).Unix(),
+		User:         user,
+	})
+}
+
+// Auth
+
+func (h *HttpServer) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	session, err := h.Auth.SignIn(r.Context(), req.Email, req.Password)
+	if err != nil {
+		writeError(w, r, errInvalidCredentials.Wrap(err))
+		return
+	}
+
+	h.writeSession(w, r, session)
+}
+
+func (h *HttpServer) HandleRefresh(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" validate:"required"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Access token lama boleh sudah expired atau tidak dikirim sama sekali
+	userToken, _ := bearerToken(r)
+
+	session, err := h.Auth.Refresh(r.Context(), userToken, req.RefreshToken)
+	if err != nil {
+		writeError(w, r, errInvalidRefresh.Wrap(err))
+		return
+	}
+
+	h.writeSession(w, r, session)
+}
+
+func (h *HttpServer) HandleLogout(w http.ResponseWriter, r *http.Request) {
+	token, err := bearerToken(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.Auth.SignOut(r.Context(), token); err != nil {
+		writeError(w, r, errUnauthorized.Wrap(err))
+		return
+	}
+
+	writeJSON(w, map[string]string{"message": i18n.T(r.Context(), "message.logged_out", nil)})
+}
+
+func (h *HttpServer) HandleMe(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+	email, _ := r.Context().Value("email").(string)
+
+	user, err := h.loadUser(r, userID, email)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, user)
+}
+
+func (h *HttpServer) HandleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email      string `json:"email" validate:"required,email"`
+		RedirectTo string `json:"redirect_to" validate:"url"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Respons selalu sama supaya endpoint ini tidak bisa dipakai untuk menebak
+	// email terdaftar. Pengecualiannya kalau pengiriman email belum dikonfigurasi,
+	// supaya user tidak menunggu email yang tidak akan datang. Error lain hanya
+	// dicatat di log.
+	err := h.Auth.SendPasswordReset(r.Context(), req.Email, req.RedirectTo)
+	if errors.Is(err, auth.ErrEmailNotConfigured) {
+		writeError(w, r, errResetUnavailable.Wrap(err))
+		return
+	}
+	if err != nil {
+		slog.WarnContext(r.Context(), "send password reset email", logging.Err(err))
+	}
+
+	writeJSON(w, map[string]string{"message": i18n.T(r.Context(), "message.password_reset_sent", nil)})
+}
+
+// HandleResetPassword hanya menerima token_hash dari link recovery. Access
+// token biasa tidak diterima: session login (atau token curian) tidak boleh
+// bisa mengganti password tanpa link recovery.
+func (h *HttpServer) HandleResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TokenHash string `json:"token_hash"`
+		Password  string `json:"password" validate:"required,min=6,max=72"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if req.TokenHash == "" {
+		writeError(w, r, errInvalidResetToken)
+		return
+	}
+	session, err := h.Auth.VerifyRecovery(r.Context(), req.TokenHash)
+	if err != nil {
+		writeError(w, r, errInvalidResetToken.Wrap(err))
+		return
+	}
+
+	if err := h.Auth.UpdatePassword(r.Context(), session.AccessToken, req.Password); err != nil {
+		writeError(w, r, apierror.BadRequest("password_reset_failed", "Failed to reset password").Wrap(err))
+		return
+	}
+
+	writeJSON(w, map[string]string{"message": i18n.T(r.Context(), "message.password_updated", nil)})
+}
+
+func (h *HttpServer) HandleResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Sama seperti forgot password: respons tidak bergantung pada hasilnya
+	if err := h.Auth.ResendVerification(r.Context(), req.Email); err != nil {
+		slog.WarnContext(r.Context(), "resend verification email", logging.Err(err))
+	}
+
+	writeJSON(w, map[string]string{"message": i18n.T(r.Context(), "message.verification_sent", nil)})
+}
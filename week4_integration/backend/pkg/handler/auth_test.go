@@ -0,0 +1,259 @@
+package handler_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nedpals/supabase-go"
+
+	"backend/pkg/auth"
+	"backend/pkg/config"
+	"backend/pkg/handler"
+	"backend/pkg/ratelimit"
+	"backend/pkg/service"
+)
+
+type session struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	ExpiresAt    int64  `json:"expires_at"`
+	User         struct {
+		ID       string `json:"id"`
+		Email    string `json:"email"`
+		Username string `json:"username"`
+		Role     string `json:"role"`
+	} `json:"user"`
+}
+
+func registerBody(email string) map[string]string {
+	return map[string]string{
+		"email":        email,
+		"password":     "secret123",
+		"username":     "budi",
+		"full_name":    "Budi Santoso",
+		"phone_number": "08123456789",
+		"street":       "Jl. Merdeka 1",
+		"city":         "Bandung",
+		"post_code":    "40111",
+	}
+}
+
+func TestRegister(t *testing.T) {
+	e := newEnv(t)
+
+	var res map[string]string
+	e.send(http.MethodPost, "/api/auth/register", "", registerBody("budi@example.com")).expect(http.StatusOK, &res)
+	if res["message"] == "" {
+		t.Errorf("missing message: %v", res)
+	}
+
+	// Metadata diteruskan ke Supabase; profil users dibuat trigger di project Supabase
+	got := e.queryInt(`SELECT COUNT(*) FROM auth.users WHERE email = 'budi@example.com' AND raw_user_meta_data->>'username' = 'budi'`)
+	if got != 1 {
+		t.Errorf("auth.users rows = %d, want 1", got)
+	}
+
+	e.send(http.MethodPost, "/api/auth/register", "", registerBody("budi@example.com")).
+		expectError(http.StatusBadRequest, "registration_failed")
+
+	body := registerBody("not-an-email")
+	body["password"] = "123"
+	apiErr := e.send(http.MethodPost, "/api/auth/register", "", body).expectError(http.StatusUnprocessableEntity, "validation_failed")
+	if len(apiErr.Details) != 2 {
+		t.Errorf("details = %+v, want email and password", apiErr.Details)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	e := newEnv(t)
+	u := e.createUser("siti", userOpts{})
+
+	var s session
+	e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": u.Email, "password": u.Password}).
+		expect(http.StatusOK, &s)
+	if s.AccessToken == "" || s.RefreshToken == "" || s.ExpiresAt == 0 {
+		t.Errorf("incomplete session: %+v", s)
+	}
+	if s.User.ID != u.ID || s.User.Username != "siti" || s.User.Role != "user" {
+		t.Errorf("user = %+v", s.User)
+	}
+
+	e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": u.Email, "password": "wrong"}).
+		expectError(http.StatusUnauthorized, "invalid_credentials")
+	e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": u.Email}).
+		expectError(http.StatusUnprocessableEntity, "validation_failed")
+	e.send(http.MethodPost, "/api/auth/login", "", "{").
+		expectError(http.StatusBadRequest, "invalid_json")
+}
+
+func TestLoginRejectsIncompleteAccounts(t *testing.T) {
+	e := newEnv(t)
+	noProfile := e.createUser("noprofile", userOpts{noProfile: true})
+	disabled := e.createUser("disabled", userOpts{disabled: true})
+
+	e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": noProfile.Email, "password": noProfile.Password}).
+		expectError(http.StatusForbidden, "user_not_registered")
+	e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": disabled.Email, "password": disabled.Password}).
+		expectError(http.StatusForbidden, "account_disabled")
+}
+
+func TestRefreshAndLogout(t *testing.T) {
+	e := newEnv(t)
+	u := e.createUser("andi", userOpts{})
+
+	var s session
+	e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": u.Email, "password": u.Password}).
+		expect(http.StatusOK, &s)
+
+	var refreshed session
+	e.send(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": s.RefreshToken}).
+		expect(http.StatusOK, &refreshed)
+	if refreshed.RefreshToken == s.RefreshToken || refreshed.User.ID != u.ID {
+		t.Errorf("refresh did not rotate session: %+v", refreshed)
+	}
+
+	// Refresh token lama sudah dipakai
+	e.send(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": s.RefreshToken}).
+		expectError(http.StatusUnauthorized, "invalid_refresh_token")
+
+	e.send(http.MethodPost, "/api/auth/logout", refreshed.AccessToken, nil).expect(http.StatusOK, nil)
+	e.send(http.MethodPost, "/api/auth/logout", "", nil).expectError(http.StatusUnauthorized, "unauthorized")
+	e.send(http.MethodPost, "/api/auth/logout", "garbage", nil).expectError(http.StatusUnauthorized, "unauthorized")
+}
+
+func TestMe(t *testing.T) {
+	e := newEnv(t)
+	u := e.createUser("rina", userOpts{role: "admin"})
+
+	var me struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	e.get("/api/auth/me", u.Token).expect(http.StatusOK, &me)
+	if me.ID != u.ID || me.Email != u.Email || me.Role != "admin" {
+		t.Errorf("me = %+v", me)
+	}
+
+	e.get("/api/auth/me", "").expectError(http.StatusUnauthorized, "unauthorized")
+	e.do(request{method: http.MethodGet, path: "/api/auth/me", header: map[string]string{"Authorization": "Token abc"}}).
+		expectError(http.StatusUnauthorized, "unauthorized")
+}
+
+func TestPasswordReset(t *testing.T) {
+	e := newEnv(t)
+	u := e.createUser("dewi", userOpts{})
+
+	// Email tidak terdaftar mendapat respons yang sama. Forgot dan reset berbagi
+	// bucket rate limit auth-email per IP, jadi pakai IP lain di sini.
+	for _, email := range []string{u.Email, "nobody@example.com"} {
+		e.do(request{method: http.MethodPost, path: "/api/auth/password/forgot", remoteAddr: "198.51.100.7:4000",
+			body: map[string]string{"email": email, "redirect_to": "https://shop.example.com/reset"}}).
+			expect(http.StatusOK, nil)
+	}
+	if got := testSupabase.recoveredEmails(); !slices.Contains(got, u.Email) {
+		t.Errorf("recover emails = %v", got)
+	}
+
+	hash := testSupabase.issueRecoveryToken(u.ID)
+	e.send(http.MethodPost, "/api/auth/password/reset", "", map[string]string{"token_hash": hash, "password": "brand-new"}).
+		expect(http.StatusOK, nil)
+
+	e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": u.Email, "password": "brand-new"}).
+		expect(http.StatusOK, nil)
+	e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": u.Email, "password": u.Password}).
+		expectError(http.StatusUnauthorized, "invalid_credentials")
+
+	// token_hash hanya bisa dipakai sekali
+	e.send(http.MethodPost, "/api/auth/password/reset", "", map[string]string{"token_hash": hash, "password": "again123"}).
+		expectError(http.StatusBadRequest, "invalid_reset_token")
+	e.send(http.MethodPost, "/api/auth/password/reset", "", map[string]string{"password": "again123"}).
+		expectError(http.StatusBadRequest, "invalid_reset_token")
+}
+
+func TestResendVerification(t *testing.T) {
+	e := newEnv(t)
+
+	e.send(http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "new@example.com"}).
+		expect(http.StatusOK, nil)
+	if got := testSupabase.resentEmails(); !slices.Equal(got, []string{"new@example.com"}) {
+		t.Errorf("resend emails = %v", got)
+	}
+
+	e.send(http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "nope"}).
+		expectError(http.StatusUnprocessableEntity, "validation_failed")
+
+	// Burst auth-email adalah 3 per IP, termasuk request yang gagal validasi
+	e.send(http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "new@example.com"}).
+		expect(http.StatusOK, nil)
+	res := e.send(http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "new@example.com"})
+	res.expectError(http.StatusTooManyRequests, "rate_limited")
+	if res.Header().Get("Retry-After") == "" {
+		t.Error("missing Retry-After header")
+	}
+}
+
+// Gagal kirim email di GoTrue tidak boleh mengubah respons (tetap tidak bisa
+// dipakai menebak email), tapi harus tercatat di log.
+func TestAuthEmailUpstreamFailure(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"code":500,"msg":"smtp: connection refused"}`))
+	}))
+	defer stub.Close()
+
+	var logs bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(prev)
+
+	cfg := &config.Config{
+		Env:             config.EnvDevelopment,
+		DefaultLanguage: "en",
+		Auth:            config.Auth{Mode: config.AuthSupabase, JWTSecret: testJWTSecret},
+		Health:          config.Health{Timeout: time.Second},
+	}
+	e := &env{t: t, router: handler.NewRouter(handler.RouterDeps{
+		Config:         cfg,
+		Auth:           auth.NewSupabase(supabase.CreateClient(stub.URL, "test-anon-key"), "test-anon-key"),
+		RateLimitStore: ratelimit.NewMemoryStore(),
+		Services:       service.NewMemory().Services(),
+	})}
+
+	var forgot, resend struct {
+		Message string `json:"message"`
+	}
+	e.send(http.MethodPost, "/api/auth/password/forgot", "", map[string]string{"email": "dewi@example.com"}).
+		expect(http.StatusOK, &forgot)
+	e.send(http.MethodPost, "/api/auth/verify/resend", "", map[string]string{"email": "dewi@example.com"}).
+		expect(http.StatusOK, &resend)
+	if forgot.Message == "" || resend.Message == "" || strings.Contains(forgot.Message+resend.Message, "smtp") {
+		t.Errorf("messages = %q, %q", forgot.Message, resend.Message)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(calls, []string{"/auth/v1/recover", "/auth/v1/resend"}) {
+		t.Errorf("upstream calls = %v", calls)
+	}
+	for _, msg := range []string{"send password reset email", "resend verification email"} {
+		if !strings.Contains(logs.String(), msg) {
+			t.Errorf("log missing %q:\n%s", msg, logs.String())
+		}
+	}
+}
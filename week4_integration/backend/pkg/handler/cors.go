@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/pkg/config"
+)
+
+// CORSPolicy adalah aturan CORS untuk satu route group.
+type CORSPolicy struct {
+	AllowedOrigins   []string // "*" berarti semua origin, tapi tanpa credentials
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORSConfig memilih policy berdasarkan prefix path terpanjang yang cocok,
+// selain itu Default yang dipakai.
+type CORSConfig struct {
+	Default CORSPolicy
+	Routes  map[string]CORSPolicy
+}
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{"Content-Type", "Authorization", "X-Request-ID"}
+	defaultCORSExposed = []string{"ETag", "X-Total-Count", "X-RateLimit-Limit", "X-RateLimit-Remaining", "Retry-After", "X-Request-ID"}
+)
+
+// NewCORSConfig membuat policy public (default) dan /api/admin dari konfigurasi.
+func NewCORSConfig(c config.CORS) CORSConfig {
+	base := CORSPolicy{
+		AllowedMethods:   defaultCORSMethods,
+		AllowedHeaders:   defaultCORSHeaders,
+		ExposedHeaders:   defaultCORSExposed,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
+
+	public := base
+	public.AllowedOrigins = c.AllowedOrigins
+
+	admin := base
+	admin.AllowedOrigins = c.AdminAllowedOrigins
+
+	return CORSConfig{
+		Default: public,
+		Routes:  map[string]CORSPolicy{"/api/admin": admin},
+	}
+}
+
+func (c CORSConfig) policyFor(path string) CORSPolicy {
+	policy, matched := c.Default, ""
+	for prefix, p := range c.Routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > len(matched) {
+			policy, matched = p, prefix
+		}
+	}
+	return policy
+}
+
+// allowOrigin mengembalikan nilai Access-Control-Allow-Origin, atau "" kalau ditolak.
+func (p CORSPolicy) allowOrigin(origin string) string {
+	if slices.Contains(p.AllowedOrigins, origin) {
+		return origin
+	}
+	if slices.Contains(p.AllowedOrigins, "*") && !p.AllowCredentials {
+		return "*"
+	}
+	return ""
+}
+
+// CORS dipasang sekali di root router supaya preflight tetap terjawab
+// walaupun route-nya tidak punya handler OPTIONS.
+func CORS(cfg CORSConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			policy := cfg.policyFor(r.URL.Path)
+			allowed := policy.allowOrigin(origin)
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			w.Header().Add("Vary", "Origin")
+			if preflight {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if allowed == "" {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", allowed)
+			if policy.AllowCredentials && allowed != "*" {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if preflight {
+				w.Header().Set("Access-Control-Allow-Methods", strings.Join(policy.AllowedMethods, ", "))
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
+				if policy.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if len(policy.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"backend/pkg/service"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type pageResponse struct {
+	Data     interface{} `json:"data"`
+	Page     int32       `json:"page"`
+	PageSize int32       `json:"page_size"`
+	Total    int64       `json:"total"`
+}
+
+// pageParams membaca ?page=&page_size= dengan default dan batas atas. page
+// dibatasi supaya offset (page-1)*page_size tetap muat di int32; halaman
+// sejauh itu tetap kosong.
+func pageParams(r *http.Request) (int32, int32) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	page = min(page, math.MaxInt32/pageSize)
+	return int32(page), int32(pageSize)
+}
+
+func writePage(w http.ResponseWriter, data interface{}, page, pageSize int32, total int64) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+	writeJSON(w, pageResponse{Data: data, Page: page, PageSize: pageSize, Total: total})
+}
+
+// customerID memvalidasi {id} sebelum body dibaca, supaya ID rusak selalu 400.
+func customerID(r *http.Request) (string, error) {
+	id := chi.URLParam(r, "id")
+	var userUUID pgtype.UUID
+	if err := userUUID.Scan(id); err != nil {
+		return "", errInvalidUUID
+	}
+	// Bentuk kanonik (huruf kecil, pakai tanda hubung) supaya cocok dengan
+	// ID user dari token dan audit log
+	return userUUID.String(), nil
+}
+
+// Mobile: customer management
+
+func (h *HttpServer) HandleListCustomers(w http.ResponseWriter, r *http.Request) {
+	search := r.URL.Query().Get("q")
+	page, pageSize := pageParams(r)
+
+	customers, total, err := h.Users.Customers(r.Context(), service.CustomerQuery{
+		Search:   search,
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if customers == nil {
+		customers = []service.Customer{}
+	}
+
+	writePage(w, customers, page, pageSize, total)
+}
+
+func (h *HttpServer) HandleGetCustomer(w http.ResponseWriter, r *http.Request) {
+	userID, err := customerID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	customer, err := h.Users.Customer(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, customer)
+}
+
+func (h *HttpServer) HandleUpdateCustomerRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := customerID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" validate:"required,oneof=user admin"`
+	}
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	actorID, _ := r.Context().Value("userID").(string)
+	if err := h.Users.SetRole(r.Context(), actorID, userID, req.Role); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "updated"})
+}
+
+func (h *HttpServer) HandleDisableCustomer(w http.ResponseWriter, r *http.Request) {
+	userID, err := customerID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	actorID, _ := r.Context().Value("userID").(string)
+	if err := h.Users.Disable(r.Context(), actorID, userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "disabled"})
+}
+
+func (h *HttpServer) HandleEnableCustomer(w http.ResponseWriter, r *http.Request) {
+	userID, err := customerID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.Users.Enable(r.Context(), userID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "enabled"})
+}
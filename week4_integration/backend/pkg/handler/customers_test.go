@@ -0,0 +1,104 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+type customerPage struct {
+	Data []struct {
+		UserID   string `json:"user_id"`
+		Username string `json:"username"`
+	} `json:"data"`
+	Page     int32 `json:"page"`
+	PageSize int32 `json:"page_size"`
+	Total    int64 `json:"total"`
+}
+
+func TestListCustomers(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	for _, name := range []string{"andi", "budi", "bunga"} {
+		e.createUser(name, userOpts{})
+	}
+
+	var page customerPage
+	res := e.get("/api/admin/customers?page=2&page_size=3", admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 4 || page.Page != 2 || page.PageSize != 3 || len(page.Data) != 1 {
+		t.Errorf("page = %+v", page)
+	}
+	if got := res.Header().Get("X-Total-Count"); got != "4" {
+		t.Errorf("X-Total-Count = %q, want 4", got)
+	}
+
+	e.get("/api/admin/customers?q=bu", admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 2 || len(page.Data) != 2 {
+		t.Errorf("search page = %+v", page)
+	}
+
+	e.get("/api/admin/customers?q=zzz", admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 0 || page.Data == nil {
+		t.Errorf("empty search should return an empty list: %+v", page)
+	}
+
+	// Offset tidak boleh overflow jadi negatif (ditolak Postgres, jadi 500)
+	e.get("/api/admin/customers?page=99999999999&page_size=100", admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 4 || len(page.Data) != 0 {
+		t.Errorf("far page = %+v", page)
+	}
+}
+
+func TestGetCustomer(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	buyer := e.createUser("buyer", userOpts{})
+	product := e.createProduct("Kaos Polos", 10)
+	e.createOrder(buyer.ID, product, 1, "done")
+	e.createOrder(buyer.ID, product, 2, "pending")
+
+	var c struct {
+		UserID     string `json:"user_id"`
+		OrderCount int64  `json:"order_count"`
+	}
+	e.get("/api/admin/customers/"+buyer.ID, admin.Token).expect(http.StatusOK, &c)
+	if c.UserID != buyer.ID || c.OrderCount != 2 {
+		t.Errorf("customer = %+v", c)
+	}
+
+	e.get("/api/admin/customers/not-a-uuid", admin.Token).expectError(http.StatusBadRequest, "invalid_id")
+	e.get("/api/admin/customers/00000000-0000-0000-0000-000000000001", admin.Token).
+		expectError(http.StatusNotFound, "customer_not_found")
+}
+
+func TestCustomerRoleAndStatus(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	buyer := e.createUser("buyer", userOpts{})
+	missing := "/api/admin/customers/00000000-0000-0000-0000-000000000001"
+
+	e.send(http.MethodPut, "/api/admin/customers/"+buyer.ID+"/role", admin.Token, map[string]string{"role": "admin"}).
+		expect(http.StatusOK, nil)
+	// Setelah dipromosikan, buyer bisa memakai /api/admin
+	e.get("/api/admin/orders", buyer.Token).expect(http.StatusOK, nil)
+
+	e.send(http.MethodPut, "/api/admin/customers/"+admin.ID+"/role", admin.Token, map[string]string{"role": "user"}).
+		expectError(http.StatusConflict, "cannot_demote_self")
+	e.send(http.MethodPut, "/api/admin/customers/"+buyer.ID+"/role", admin.Token, map[string]string{"role": "owner"}).
+		expectError(http.StatusUnprocessableEntity, "validation_failed")
+	e.send(http.MethodPut, missing+"/role", admin.Token, map[string]string{"role": "user"}).
+		expectError(http.StatusNotFound, "customer_not_found")
+
+	e.send(http.MethodDelete, "/api/admin/customers/"+buyer.ID, admin.Token, nil).expect(http.StatusOK, nil)
+	e.get("/api/admin/orders", buyer.Token).expectError(http.StatusForbidden, "account_disabled")
+	e.get("/api/auth/me", buyer.Token).expectError(http.StatusForbidden, "account_disabled")
+
+	e.send(http.MethodPost, "/api/admin/customers/"+buyer.ID+"/enable", admin.Token, nil).expect(http.StatusOK, nil)
+	e.get("/api/admin/orders", buyer.Token).expect(http.StatusOK, nil)
+
+	e.send(http.MethodDelete, "/api/admin/customers/"+admin.ID, admin.Token, nil).
+		expectError(http.StatusConflict, "cannot_disable_self")
+	e.send(http.MethodDelete, missing, admin.Token, nil).expectError(http.StatusNotFound, "customer_not_found")
+	e.send(http.MethodPost, missing+"/enable", admin.Token, nil).expectError(http.StatusNotFound, "customer_not_found")
+	e.send(http.MethodPost, "/api/admin/customers/42/enable", admin.Token, nil).
+		expectError(http.StatusBadRequest, "invalid_id")
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	"backend/pkg/apierror"
+	"backend/pkg/service"
+)
+
+// Error HTTP/auth yang dipakai lebih dari satu handler. Error domain (produk,
+// order, user) ada di package service. Code-nya bagian dari kontrak API,
+// jangan diganti setelah dirilis.
+var (
+	errUnauthorized       = apierror.Unauthorized("Missing or invalid access token")
+	errInvalidCredentials = apierror.New(http.StatusUnauthorized, "invalid_credentials", "Invalid email or password")
+	errInvalidRefresh     = apierror.New(http.StatusUnauthorized, "invalid_refresh_token", "Invalid or expired refresh token")
+	errInvalidResetToken  = apierror.BadRequest("invalid_reset_token", "Invalid or expired reset token")
+	errResetUnavailable   = apierror.New(http.StatusServiceUnavailable, "password_reset_unavailable", "Password reset by email is not configured")
+
+	errInvalidJSON = apierror.ErrInvalidJSON
+	errInvalidUUID = service.ErrInvalidUUID
+)
+
+// writeError menulis error dalam envelope JSON standar (lihat package apierror).
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	apierror.Write(w, r, err)
+}
@@ -0,0 +1,286 @@
+package handler_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fakeSupabase meniru endpoint GoTrue (/auth/v1/...) yang dipanggil backend.
+// User disimpan di auth.users database test, jadi handler dan fake melihat
+// data yang sama seperti di Supabase asli.
+type fakeSupabase struct {
+	*httptest.Server
+	db *pgxpool.Pool
+
+	mu             sync.Mutex
+	refreshTokens  map[string]string // refresh token -> user id
+	recoveryTokens map[string]string // token_hash -> user id
+	recoverEmails  []string
+	resendEmails   []string
+}
+
+type fakeUser struct {
+	ID           string         `json:"id"`
+	Aud          string         `json:"aud"`
+	Role         string         `json:"role"`
+	Email        string         `json:"email"`
+	UserMetadata map[string]any `json:"user_metadata"`
+}
+
+func newFakeSupabase(db *pgxpool.Pool) *fakeSupabase {
+	f := &fakeSupabase{db: db}
+	f.reset()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /auth/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		writeFakeJSON(w, http.StatusOK, map[string]string{"name": "GoTrue", "version": "fake"})
+	})
+	mux.HandleFunc("POST /auth/v1/signup", f.signup)
+	mux.HandleFunc("POST /auth/v1/token", f.token)
+	mux.HandleFunc("POST /auth/v1/logout", f.logout)
+	mux.HandleFunc("POST /auth/v1/recover", f.recover)
+	mux.HandleFunc("POST /auth/v1/verify", f.verify)
+	mux.HandleFunc("PUT /auth/v1/user", f.updateUser)
+	mux.HandleFunc("POST /auth/v1/resend", f.resend)
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+// reset mengosongkan state di memori; data auth.users dihapus oleh resetDB.
+func (f *fakeSupabase) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refreshTokens = map[string]string{}
+	f.recoveryTokens = map[string]string{}
+	f.recoverEmails = nil
+	f.resendEmails = nil
+}
+
+// issueRecoveryToken membuat token_hash seperti yang ada di link email reset password.
+func (f *fakeSupabase) issueRecoveryToken(userID string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	hash := randomToken()
+	f.recoveryTokens[hash] = userID
+	return hash
+}
+
+func (f *fakeSupabase) recoveredEmails() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.recoverEmails...)
+}
+
+func (f *fakeSupabase) resentEmails() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.resendEmails...)
+}
+
+func (f *fakeSupabase) signup(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email    string         `json:"email"`
+		Password string         `json:"password"`
+		Data     map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	meta, _ := json.Marshal(req.Data)
+	var id string
+	err := f.db.QueryRow(r.Context(), `
+		INSERT INTO auth.users (email, encrypted_password, raw_user_meta_data)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (email) DO NOTHING
+		RETURNING id::text`, req.Email, req.Password, meta).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		writeFakeError(w, http.StatusUnprocessableEntity, "User already registered")
+		return
+	}
+	if err != nil {
+		writeFakeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeFakeJSON(w, http.StatusOK, fakeUser{ID: id, Aud: "authenticated", Role: "authenticated", Email: req.Email, UserMetadata: req.Data})
+}
+
+func (f *fakeSupabase) token(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email        string `json:"email"`
+		Password     string `json:"password"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeFakeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var userID string
+	switch r.URL.Query().Get("grant_type") {
+	case "password":
+		err := f.db.QueryRow(r.Context(),
+			"SELECT id::text FROM auth.users WHERE email = $1 AND encrypted_password = $2",
+			req.Email, req.Password).Scan(&userID)
+		if err != nil {
+			writeGrantError(w, "Invalid login credentials")
+			return
+		}
+	case "refresh_token":
+		f.mu.Lock()
+		id, ok := f.refreshTokens[req.RefreshToken]
+		delete(f.refreshTokens, req.RefreshToken)
+		f.mu.Unlock()
+		if !ok {
+			writeGrantError(w, "Invalid Refresh Token: Refresh Token Not Found")
+			return
+		}
+		userID = id
+	default:
+		writeGrantError(w, "unsupported grant type")
+		return
+	}
+
+	f.writeSession(w, r, userID)
+}
+
+func (f *fakeSupabase) writeSession(w http.ResponseWriter, r *http.Request, userID string) {
+	var email string
+	if err := f.db.QueryRow(r.Context(), "SELECT email FROM auth.users WHERE id = $1", userID).Scan(&email); err != nil {
+		writeFakeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	refresh := randomToken()
+	f.mu.Lock()
+	f.refreshTokens[refresh] = userID
+	f.mu.Unlock()
+
+	writeFakeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  signToken(userID, email),
+		"token_type":    "bearer",
+		"expires_in":    3600,
+		"refresh_token": refresh,
+		"user":          fakeUser{ID: userID, Aud: "authenticated", Role: "authenticated", Email: email},
+	})
+}
+
+func (f *fakeSupabase) logout(w http.ResponseWriter, r *http.Request) {
+	if _, ok := f.userFromToken(r); !ok {
+		writeFakeError(w, http.StatusUnauthorized, "invalid JWT")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeSupabase) recover(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	f.mu.Lock()
+	f.recoverEmails = append(f.recoverEmails, req.Email)
+	f.mu.Unlock()
+	writeFakeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (f *fakeSupabase) verify(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Type      string `json:"type"`
+		TokenHash string `json:"token_hash"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	f.mu.Lock()
+	userID, ok := f.recoveryTokens[req.TokenHash]
+	delete(f.recoveryTokens, req.TokenHash)
+	f.mu.Unlock()
+	if req.Type != "recovery" || !ok {
+		writeFakeError(w, http.StatusForbidden, "Token has expired or is invalid")
+		return
+	}
+
+	f.writeSession(w, r, userID)
+}
+
+func (f *fakeSupabase) updateUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := f.userFromToken(r)
+	if !ok {
+		writeFakeError(w, http.StatusUnauthorized, "invalid JWT")
+		return
+	}
+
+	var req struct {
+		Password string `json:"password"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	var email string
+	err := f.db.QueryRow(r.Context(),
+		"UPDATE auth.users SET encrypted_password = $2 WHERE id = $1 RETURNING email",
+		userID, req.Password).Scan(&email)
+	if err != nil {
+		writeFakeError(w, http.StatusNotFound, "User not found")
+		return
+	}
+	writeFakeJSON(w, http.StatusOK, fakeUser{ID: userID, Aud: "authenticated", Role: "authenticated", Email: email})
+}
+
+func (f *fakeSupabase) resend(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	f.mu.Lock()
+	f.resendEmails = append(f.resendEmails, req.Email)
+	f.mu.Unlock()
+	writeFakeJSON(w, http.StatusOK, map[string]any{})
+}
+
+// userFromToken memvalidasi bearer token dengan secret test, seperti GoTrue.
+func (f *fakeSupabase) userFromToken(r *http.Request) (string, bool) {
+	raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return "", false
+	}
+	token, err := jwt.Parse(raw, func(*jwt.Token) (any, error) { return []byte(testJWTSecret), nil })
+	if err != nil || !token.Valid {
+		return "", false
+	}
+	sub, _ := token.Claims.(jwt.MapClaims)["sub"].(string)
+	return sub, sub != ""
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeFakeError(w http.ResponseWriter, status int, msg string) {
+	writeFakeJSON(w, status, map[string]any{"code": status, "msg": msg})
+}
+
+func writeGrantError(w http.ResponseWriter, msg string) {
+	writeFakeJSON(w, http.StatusBadRequest, map[string]any{"error_code": "invalid_grant", "msg": msg})
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
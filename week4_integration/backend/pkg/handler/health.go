@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"backend/pkg/logging"
+	"backend/pkg/version"
+)
+
+// checkResult sengaja tidak memuat pesan error asli: /readyz publik, dan
+// error pgx/Supabase bisa berisi host, user database, atau URL upstream.
+// Detailnya ada di log.
+type checkResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// checkUnreachable adalah satu-satunya nilai checkResult.Error.
+const checkUnreachable = "unreachable"
+
+type readinessResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+type versionResponse struct {
+	version.Info
+	SchemaVersion *int64 `json:"schema_version"`
+}
+
+func writeStatusJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// HandleHealthz hanya menandakan proses hidup, tanpa menyentuh dependency,
+// jadi bisa dipanggil walaupun database sedang down.
+func HandleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeStatusJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// NotReady dipakai api/index.go saat router belum bisa dibangun karena
+// database belum terhubung.
+func NotReady(w http.ResponseWriter, err error) {
+	slog.Warn("readiness check failed", "check", "database", logging.Err(err))
+	writeStatusJSON(w, http.StatusServiceUnavailable, readinessResponse{
+		Status: "unavailable",
+		Checks: map[string]checkResult{"database": {Status: "down", Error: checkUnreachable}},
+	})
+}
+
+func (h *HttpServer) runCheck(ctx context.Context, name string, check func(context.Context) error) checkResult {
+	ctx, cancel := context.WithTimeout(ctx, h.Config.Health.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	res := checkResult{Status: "up", LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		slog.WarnContext(ctx, "readiness check failed", "check", name, logging.Err(err))
+		res.Status, res.Error = "down", checkUnreachable
+	}
+	return res
+}
+
+// HandleReadyz mengecek dependency yang dibutuhkan untuk melayani request.
+func (h *HttpServer) HandleReadyz(w http.ResponseWriter, r *http.Request) {
+	resp := readinessResponse{Status: "ok", Checks: map[string]checkResult{}}
+
+	resp.Checks["database"] = h.runCheck(r.Context(), "database", h.DB.Ping)
+	if h.Config.Health.CheckSupabase {
+		resp.Checks["supabase_auth"] = h.runCheck(r.Context(), "supabase_auth", h.Auth.Ping)
+	}
+
+	status := http.StatusOK
+	for _, c := range resp.Checks {
+		if c.Status != "up" {
+			resp.Status, status = "unavailable", http.StatusServiceUnavailable
+		}
+	}
+	writeStatusJSON(w, status, resp)
+}
+
+// HandleVersion menampilkan info build dan versi migrasi schema terakhir.
+// schema_version null kalau tabel schema_migrations belum ada.
+func (h *HttpServer) HandleVersion(w http.ResponseWriter, r *http.Request) {
+	resp := versionResponse{Info: version.Get()}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Config.Health.Timeout)
+	defer cancel()
+
+	err := h.DB.QueryRow(ctx, "SELECT MAX(version) FROM schema_migrations").Scan(&resp.SchemaVersion)
+	var pgErr *pgconn.PgError
+	if err != nil && !(errors.As(err, &pgErr) && pgErr.Code == "42P01") {
+		writeError(w, r, err)
+		return
+	}
+
+	writeStatusJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,106 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"backend/pkg/handler"
+)
+
+func TestProbes(t *testing.T) {
+	e := newEnv(t)
+
+	e.get("/healthz", "").expect(http.StatusOK, nil)
+
+	var ready struct {
+		Status string `json:"status"`
+		Checks map[string]struct {
+			Status string `json:"status"`
+		} `json:"checks"`
+	}
+	e.get("/readyz", "").expect(http.StatusOK, &ready)
+	if ready.Status != "ok" || ready.Checks["database"].Status != "up" || ready.Checks["supabase_auth"].Status != "up" {
+		t.Errorf("readyz = %+v", ready)
+	}
+
+	var version struct {
+		SchemaVersion *int64 `json:"schema_version"`
+	}
+	e.get("/version", "").expect(http.StatusOK, &version)
+	if version.SchemaVersion == nil || *version.SchemaVersion != testSchema {
+		t.Errorf("schema_version = %v, want %d", version.SchemaVersion, testSchema)
+	}
+}
+
+// Detail error koneksi hanya masuk log, tidak pernah ke body /readyz publik
+func TestNotReadyHidesError(t *testing.T) {
+	w := httptest.NewRecorder()
+	handler.NotReady(w, errors.New(`failed to connect to user=postgres database=shop host=db.internal`))
+
+	body := w.Body.String()
+	if w.Code != http.StatusServiceUnavailable || strings.Contains(body, "db.internal") || strings.Contains(body, "postgres") {
+		t.Errorf("status = %d, body = %s", w.Code, body)
+	}
+	if !strings.Contains(body, `"error":"unreachable"`) {
+		t.Errorf("body = %s, want error code unreachable", body)
+	}
+}
+
+func TestUnknownRoutes(t *testing.T) {
+	e := newEnv(t)
+
+	e.get("/api/nope", "").expectError(http.StatusNotFound, "not_found")
+	e.send(http.MethodDelete, "/api/products", "", nil).expectError(http.StatusMethodNotAllowed, "method_not_allowed")
+}
+
+// TestAdminOnly memastikan setiap route /api/admin menolak request sebelum
+// handler berjalan, apa pun body-nya.
+func TestAdminOnly(t *testing.T) {
+	e := newEnv(t)
+	user := e.createUser("user", userOpts{})
+	disabledAdmin := e.createUser("disabledadmin", userOpts{role: "admin", disabled: true})
+	ghost := e.createUser("ghost", userOpts{noProfile: true})
+
+	routes := []struct{ method, path string }{
+		{http.MethodGet, "/api/admin/products"},
+		{http.MethodPost, "/api/admin/products"},
+		{http.MethodPut, "/api/admin/products/1"},
+		{http.MethodDelete, "/api/admin/products/1"},
+		{http.MethodGet, "/api/admin/orders"},
+		{http.MethodPost, "/api/admin/orders"},
+		{http.MethodPut, "/api/admin/orders/1/status"},
+		{http.MethodGet, "/api/admin/customers"},
+		{http.MethodGet, "/api/admin/customers/" + user.ID},
+		{http.MethodPut, "/api/admin/customers/" + user.ID + "/role"},
+		{http.MethodDelete, "/api/admin/customers/" + user.ID},
+		{http.MethodPost, "/api/admin/customers/" + user.ID + "/enable"},
+	}
+	callers := []struct {
+		name   string
+		token  string
+		status int
+		code   string
+	}{
+		{"no token", "", http.StatusUnauthorized, "unauthorized"},
+		{"wrong secret", signTokenWith("another-secret", user.ID, user.Email), http.StatusUnauthorized, "unauthorized"},
+		{"not registered", ghost.Token, http.StatusForbidden, "user_not_registered"},
+		{"regular user", user.Token, http.StatusForbidden, "admin_required"},
+		{"disabled admin", disabledAdmin.Token, http.StatusForbidden, "account_disabled"},
+	}
+
+	for _, c := range callers {
+		for _, route := range routes {
+			t.Run(c.name+" "+route.method+" "+route.path, func(t *testing.T) {
+				e := &env{t: t, router: e.router}
+				e.do(request{method: route.method, path: route.path, token: c.token, body: "{}"}).expectError(c.status, c.code)
+			})
+		}
+	}
+
+	if got := e.queryInt("SELECT COUNT(*) FROM users WHERE role = 'user' AND disabled_at IS NULL"); got != 1 {
+		t.Errorf("rejected requests changed data: %d active users", got)
+	}
+}
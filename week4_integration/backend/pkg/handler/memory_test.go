@@ -0,0 +1,496 @@
+package handler_test
+
+// Unit test handler di atas service.Memory, jadi tetap jalan tanpa
+// TEST_DATABASE_URL. Aturan yang butuh Postgres sungguhan (constraint,
+// transaksi, migrasi) tetap dites di integration test.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/pkg/auth"
+	"backend/pkg/config"
+	"backend/pkg/handler"
+	"backend/pkg/ratelimit"
+	"backend/pkg/service"
+)
+
+// memoryEnv memakai service.Memory dan auth.Local di atas auth.MemoryStore,
+// jadi tidak ada Postgres maupun Supabase.
+type memoryEnv struct {
+	*env
+	mem      *service.Memory
+	accounts *auth.MemoryStore
+	local    *auth.Local
+}
+
+func newMemoryEnv(t *testing.T) *memoryEnv {
+	t.Helper()
+
+	mem := service.NewMemory()
+	accounts := auth.NewMemoryStore()
+	local := auth.NewLocal(accounts, testJWTSecret)
+	cfg := &config.Config{
+		Env:             config.EnvDevelopment,
+		DefaultLanguage: "en",
+		Auth:            config.Auth{Mode: config.AuthStandalone, JWTSecret: testJWTSecret},
+		Health:          config.Health{Timeout: time.Second},
+	}
+	router := handler.NewRouter(handler.RouterDeps{
+		Config:         cfg,
+		Auth:           local,
+		RateLimitStore: ratelimit.NewMemoryStore(),
+		Services:       mem.Services(),
+	})
+	return &memoryEnv{env: &env{t: t, router: router}, mem: mem, accounts: accounts, local: local}
+}
+
+func addMemoryUser(mem *service.Memory, username, role string) testUser {
+	u := testUser{Email: username + "@example.com", Username: username}
+	u.ID = mem.AddUser(service.MemoryUser{
+		Email:         u.Email,
+		EmailVerified: true,
+		Registered:    true,
+		Profile: service.Profile{
+			Username:    username,
+			FullName:    "User " + username,
+			PhoneNumber: "08123456789",
+			Role:        role,
+		},
+	})
+	u.Token = signToken(u.ID, u.Email)
+	return u
+}
+
+// Limit per IP sebelum auth punya bucket sendiri, jadi katalog yang ramai
+// tidak membuat /api/me ikut kena 429
+func TestMemoryAuthIPLimitSeparateFromPublic(t *testing.T) {
+	e := newMemoryEnv(t)
+	buyer := addMemoryUser(e.mem, "buyer", "user")
+
+	for range ratelimit.PublicPolicy.Burst {
+		e.get("/api/products", "").expect(http.StatusOK, nil)
+	}
+	e.get("/api/products", "").expectError(http.StatusTooManyRequests, "rate_limited")
+	e.get("/api/me", buyer.Token).expect(http.StatusOK, nil)
+}
+
+func TestMemoryAdminOnly(t *testing.T) {
+	e := newMemoryEnv(t)
+	mem := e.mem
+	admin := addMemoryUser(mem, "admin", "admin")
+	buyer := addMemoryUser(mem, "buyer", "user")
+	disabled := mem.AddUser(service.MemoryUser{Registered: true, Disabled: true, Profile: service.Profile{Role: "admin"}})
+	unregistered := mem.AddUser(service.MemoryUser{})
+
+	e.get("/api/admin/products", admin.Token).expect(http.StatusOK, nil)
+	e.get("/api/admin/products", "").expectError(http.StatusUnauthorized, "unauthorized")
+	e.get("/api/admin/products", buyer.Token).expectError(http.StatusForbidden, "admin_required")
+	e.get("/api/admin/products", signToken(disabled, "")).expectError(http.StatusForbidden, "account_disabled")
+	e.get("/api/admin/products", signToken(unregistered, "")).expectError(http.StatusForbidden, "user_not_registered")
+}
+
+func TestMemoryRateLimitBeforeAuth(t *testing.T) {
+	e := newMemoryEnv(t)
+	buyer := addMemoryUser(e.mem, "buyer", "user")
+
+	// Request yang ditolak AdminOnly tetap menghabiskan bucket IP
+	var res *response
+	for range ratelimit.AuthIPPolicy.Burst + 1 {
+		res = e.do(request{method: http.MethodGet, path: "/api/admin/products", token: buyer.Token, remoteAddr: "203.0.113.5:1000"})
+	}
+	res.expectError(http.StatusTooManyRequests, "rate_limited")
+
+	// IP lain tidak ikut kena
+	e.do(request{method: http.MethodGet, path: "/api/me", token: buyer.Token, remoteAddr: "203.0.113.6:1000"}).expect(http.StatusOK, nil)
+}
+
+func TestMemoryProducts(t *testing.T) {
+	e := newMemoryEnv(t)
+	mem := e.mem
+	admin := addMemoryUser(mem, "admin", "admin")
+	soldOut := mem.AddProduct(service.ProductInput{Name: "Sold out", Category: "Hoodie", Price: 100000})
+
+	var created struct {
+		ProductID int32 `json:"product_id"`
+	}
+	e.send(http.MethodPost, "/api/admin/products", admin.Token, map[string]any{
+		"name": "Hoodie", "category": "Hoodie", "description": "Warm", "price": 150000, "stock": 5,
+	}).expect(http.StatusOK, &created)
+
+	var public []map[string]any
+	e.get("/api/products", "").expect(http.StatusOK, &public)
+	if len(public) != 1 || public[0]["product_id"] != float64(created.ProductID) {
+		t.Fatalf("public products = %v, want only %d", public, created.ProductID)
+	}
+	if _, ok := public[0]["description"]; ok {
+		t.Errorf("public listing includes description: %v", public[0])
+	}
+
+	var detail service.Product
+	e.get(fmt.Sprintf("/api/products/%d", created.ProductID), "").expect(http.StatusOK, &detail)
+	if detail.Description != "Warm" || detail.Stock != 5 {
+		t.Errorf("detail = %+v", detail)
+	}
+	e.get(fmt.Sprintf("/api/products/%d", soldOut), "").expectError(http.StatusNotFound, "product_not_found")
+	e.get(fmt.Sprintf("/api/products/%d", soldOut+100), "").expectError(http.StatusNotFound, "product_not_found")
+
+	mem.AddOrder(admin.ID, soldOut, 1, "pending")
+	e.send(http.MethodDelete, fmt.Sprintf("/api/admin/products/%d", soldOut), admin.Token, nil).
+		expectError(http.StatusConflict, "resource_in_use")
+	e.send(http.MethodDelete, fmt.Sprintf("/api/admin/products/%d", created.ProductID), admin.Token, nil).
+		expect(http.StatusOK, nil)
+
+	// Produk yang tidak ada (atau sudah dihapus)
+	e.send(http.MethodDelete, fmt.Sprintf("/api/admin/products/%d", created.ProductID), admin.Token, nil).
+		expectError(http.StatusNotFound, "product_not_found")
+	e.send(http.MethodPut, fmt.Sprintf("/api/admin/products/%d", created.ProductID), admin.Token, map[string]any{
+		"name": "Hoodie", "category": "Hoodie", "price": 150000, "stock": 5,
+	}).expectError(http.StatusNotFound, "product_not_found")
+}
+
+func TestMemoryCreateOrder(t *testing.T) {
+	e := newMemoryEnv(t)
+	mem := e.mem
+	admin := addMemoryUser(mem, "admin", "admin")
+	buyer := addMemoryUser(mem, "buyer", "user")
+	unverified := mem.AddUser(service.MemoryUser{Registered: true})
+	product := mem.AddProduct(service.ProductInput{Name: "Hoodie", Category: "Hoodie", Price: 150000, Stock: 3})
+
+	order := func(userID string, extra map[string]any) *response {
+		body := map[string]any{"user_id": userID, "product_id": product, "quantity": 1, "total_amount": 150000}
+		for k, v := range extra {
+			body[k] = v
+		}
+		return e.send(http.MethodPost, "/api/admin/orders", admin.Token, body)
+	}
+
+	order(unverified, nil).expectError(http.StatusForbidden, "email_not_verified")
+	order("00000000-0000-0000-0000-000000000000", nil).expectError(http.StatusNotFound, "user_not_found")
+	order(buyer.ID, map[string]any{"address_id": 99}).expectError(http.StatusNotFound, "address_not_found")
+	order(buyer.ID, map[string]any{"product_id": product + 1}).expectError(http.StatusUnprocessableEntity, "invalid_reference")
+
+	e.send(http.MethodPost, "/api/me/addresses", buyer.Token, map[string]any{
+		"label": "Rumah", "recipient_name": "Budi", "phone_number": "0812", "street": "Jl. Asia Afrika 8", "city": "Bandung", "post_code": "40111",
+	}).expect(http.StatusCreated, nil)
+	order(buyer.ID, nil).expect(http.StatusOK, nil)
+
+	// Buku alamat kosong: snapshot diambil dari alamat di profil
+	legacy := mem.AddUser(service.MemoryUser{EmailVerified: true, Registered: true, Profile: service.Profile{
+		FullName: "Sari", PhoneNumber: "0813", Role: "user", Street: "Jl. Braga 5", City: "Bandung", PostCode: "40111",
+	}})
+	order(legacy, nil).expect(http.StatusOK, nil)
+
+	var orders []struct {
+		Status            string `json:"status"`
+		StatusLabel       string `json:"status_label"`
+		ShipRecipientName string `json:"ship_recipient_name"`
+		ShipStreet        string `json:"ship_street"`
+	}
+	e.get("/api/admin/orders", admin.Token).expect(http.StatusOK, &orders)
+	if len(orders) != 2 || orders[0].Status != "pending" || orders[0].ShipStreet != "Jl. Asia Afrika 8" {
+		t.Fatalf("orders = %+v", orders)
+	}
+	if orders[1].ShipStreet != "Jl. Braga 5" || orders[1].ShipRecipientName != "Sari" {
+		t.Errorf("profile fallback = %+v", orders[1])
+	}
+}
+
+func TestMemoryOrderDoneDecreasesStock(t *testing.T) {
+	e := newMemoryEnv(t)
+	mem := e.mem
+	admin := addMemoryUser(mem, "admin", "admin")
+	product := mem.AddProduct(service.ProductInput{Name: "Hoodie", Category: "Hoodie", Price: 150000, Stock: 2})
+	first := mem.AddOrder(admin.ID, product, 2, "process")
+	second := mem.AddOrder(admin.ID, product, 1, "process")
+
+	status := func(id int32, s string) *response {
+		return e.send(http.MethodPut, fmt.Sprintf("/api/admin/orders/%d/status", id), admin.Token, map[string]string{"status": s})
+	}
+
+	status(first, "done").expect(http.StatusOK, nil)
+	// Mengulang "done" tidak mengurangi stok lagi
+	status(first, "done").expect(http.StatusOK, nil)
+	status(second, "done").expectError(http.StatusConflict, "insufficient_stock")
+	status(999, "done").expectError(http.StatusNotFound, "order_not_found")
+
+	var orders []struct {
+		Status string `json:"status"`
+	}
+	e.get("/api/admin/orders", admin.Token).expect(http.StatusOK, &orders)
+	if orders[0].Status != "done" || orders[1].Status != "process" {
+		t.Errorf("statuses = %+v, want done then process", orders)
+	}
+
+	var products []service.Product
+	e.get("/api/admin/products", admin.Token).expect(http.StatusOK, &products)
+	if products[0].Stock != 0 {
+		t.Errorf("stock = %d, want 0", products[0].Stock)
+	}
+}
+
+func TestMemoryAddresses(t *testing.T) {
+	e := newMemoryEnv(t)
+	mem := e.mem
+	buyer := addMemoryUser(mem, "buyer", "user")
+	other := addMemoryUser(mem, "other", "user")
+
+	add := func(u testUser, label string, isDefault bool) service.Address {
+		var a service.Address
+		e.send(http.MethodPost, "/api/me/addresses", u.Token, map[string]any{
+			"label": label, "recipient_name": "Budi", "phone_number": "0812", "street": "Jl. Dago 1",
+			"city": "Bandung", "post_code": "40135", "is_default": isDefault,
+		}).expect(http.StatusCreated, &a)
+		return a
+	}
+
+	home := add(buyer, "Rumah", false)
+	office := add(buyer, "Kantor", false)
+	if !home.IsDefault || office.IsDefault {
+		t.Fatalf("first address must become default: home=%v office=%v", home.IsDefault, office.IsDefault)
+	}
+
+	e.send(http.MethodPut, fmt.Sprintf("/api/me/addresses/%d/default", office.AddressID), buyer.Token, nil).expect(http.StatusOK, nil)
+	var list []service.Address
+	e.get("/api/me/addresses", buyer.Token).expect(http.StatusOK, &list)
+	if len(list) != 2 || list[0].AddressID != office.AddressID || list[1].IsDefault {
+		t.Fatalf("addresses = %+v, want office first as only default", list)
+	}
+
+	// Alamat user lain tidak terlihat
+	e.send(http.MethodDelete, fmt.Sprintf("/api/me/addresses/%d", home.AddressID), other.Token, nil).
+		expectError(http.StatusNotFound, "address_not_found")
+
+	var me struct {
+		Addresses []service.Address `json:"addresses"`
+	}
+	e.get("/api/me", other.Token).expect(http.StatusOK, &me)
+	if me.Addresses == nil || len(me.Addresses) != 0 {
+		t.Errorf("addresses = %v, want empty list", me.Addresses)
+	}
+}
+
+func TestMemoryCustomers(t *testing.T) {
+	e := newMemoryEnv(t)
+	mem := e.mem
+	admin := addMemoryUser(mem, "admin", "admin")
+	buyer := addMemoryUser(mem, "buyer", "user")
+
+	e.send(http.MethodPut, "/api/admin/customers/"+admin.ID+"/role", admin.Token, map[string]string{"role": "user"}).
+		expectError(http.StatusConflict, "cannot_demote_self")
+	e.send(http.MethodDelete, "/api/admin/customers/"+admin.ID, admin.Token, nil).
+		expectError(http.StatusConflict, "cannot_disable_self")
+	// ID yang sama dalam huruf besar tetap terhitung diri sendiri
+	upper := strings.ToUpper(admin.ID)
+	e.send(http.MethodPut, "/api/admin/customers/"+upper+"/role", admin.Token, map[string]string{"role": "user"}).
+		expectError(http.StatusConflict, "cannot_demote_self")
+	e.send(http.MethodDelete, "/api/admin/customers/"+upper, admin.Token, nil).
+		expectError(http.StatusConflict, "cannot_disable_self")
+	e.get("/api/admin/customers/not-a-uuid", admin.Token).expectError(http.StatusBadRequest, "invalid_id")
+
+	e.send(http.MethodDelete, "/api/admin/customers/"+buyer.ID, admin.Token, nil).expect(http.StatusOK, nil)
+	e.get("/api/me", buyer.Token).expectError(http.StatusForbidden, "account_disabled")
+	// Access token lama tidak bisa dipakai untuk self-service lain
+	e.send(http.MethodPatch, "/api/me", buyer.Token, map[string]string{"full_name": "Baru"}).
+		expectError(http.StatusForbidden, "account_disabled")
+	e.get("/api/me/addresses", buyer.Token).expectError(http.StatusForbidden, "account_disabled")
+	e.send(http.MethodPost, "/api/me/addresses", buyer.Token, map[string]any{
+		"label": "Rumah", "recipient_name": "Budi", "phone_number": "0812", "street": "Jl. Dago 1", "city": "Bandung", "post_code": "40135",
+	}).expectError(http.StatusForbidden, "account_disabled")
+	if _, err := mem.Services().Users.CreateAddress(context.Background(), buyer.ID, service.AddressInput{Label: "Rumah"}); !errors.Is(err, service.ErrAccountDisabled) {
+		t.Errorf("CreateAddress for disabled user: err = %v, want ErrAccountDisabled", err)
+	}
+
+	var page struct {
+		Data  []service.Customer `json:"data"`
+		Total int64              `json:"total"`
+	}
+	e.get("/api/admin/customers?q=buy", admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 1 || len(page.Data) != 1 || !page.Data[0].DisabledAt.Valid {
+		t.Fatalf("customers = %+v", page)
+	}
+
+	// Halaman yang sangat jauh tidak membuat offset overflow
+	for _, path := range []string{"/api/admin/customers?page=99999999999", "/api/admin/audit?page=2147483647&page_size=100"} {
+		e.get(path, admin.Token).expect(http.StatusOK, &page)
+		if len(page.Data) != 0 {
+			t.Errorf("%s: data = %+v, want empty", path, page.Data)
+		}
+	}
+}
+
+func TestMemoryLocalAuth(t *testing.T) {
+	e := newMemoryEnv(t)
+	ctx := context.Background()
+
+	// Tanpa pengirim email, forgot password gagal terang-terangan
+	e.do(request{method: http.MethodPost, path: "/api/auth/password/forgot", remoteAddr: "198.51.100.9:1234",
+		body: map[string]string{"email": "budi@example.com"}}).
+		expectError(http.StatusServiceUnavailable, "password_reset_unavailable")
+
+	var sent []string
+	e.local.SendEmail = func(ctx context.Context, to, kind, token, redirectTo string) error {
+		sent = append(sent, token)
+		return nil
+	}
+
+	e.send(http.MethodPost, "/api/auth/register", "", map[string]string{
+		"email": "Budi@Example.com", "password": "rahasia1", "username": "budi", "full_name": "Budi",
+		"phone_number": "0812", "street": "Jl. Braga 1", "city": "Bandung", "post_code": "40111",
+	}).expect(http.StatusOK, nil)
+	e.send(http.MethodPost, "/api/auth/register", "", map[string]string{
+		"email": "budi@example.com", "password": "rahasia1", "username": "budi2", "full_name": "Budi",
+		"phone_number": "0812", "street": "Jl. Braga 1", "city": "Bandung", "post_code": "40111",
+	}).expectError(http.StatusBadRequest, "registration_failed")
+
+	// Profil dibuat di luar provider (di Supabase lewat trigger)
+	account, err := e.accounts.AccountByEmail(ctx, "budi@example.com")
+	if err != nil {
+		t.Fatalf("account not stored: %v", err)
+	}
+	if account.Metadata["username"] != "budi" {
+		t.Errorf("metadata = %v", account.Metadata)
+	}
+	e.mem.AddUser(service.MemoryUser{ID: account.ID, Email: account.Email, EmailVerified: true, Registered: true,
+		Profile: service.Profile{Username: "budi", FullName: "Budi"}})
+
+	login := func(password string) *response {
+		return e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": "budi@example.com", "password": password})
+	}
+	login("salah").expectError(http.StatusUnauthorized, "invalid_credentials")
+
+	var session struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		User         struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"user"`
+	}
+	login("rahasia1").expect(http.StatusOK, &session)
+	if session.User.ID != account.ID || session.User.Username != "budi" {
+		t.Fatalf("session user = %+v", session.User)
+	}
+	e.get("/api/auth/me", session.AccessToken).expect(http.StatusOK, nil)
+
+	// Refresh token diputar: yang lama tidak bisa dipakai lagi
+	oldRefresh := session.RefreshToken
+	e.send(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": oldRefresh}).expect(http.StatusOK, &session)
+	e.send(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": oldRefresh}).
+		expectError(http.StatusUnauthorized, "invalid_refresh_token")
+
+	e.send(http.MethodPost, "/api/auth/logout", session.AccessToken, nil).expect(http.StatusOK, nil)
+	e.send(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": session.RefreshToken}).
+		expectError(http.StatusUnauthorized, "invalid_refresh_token")
+
+	e.send(http.MethodPost, "/api/auth/password/forgot", "", map[string]string{"email": "budi@example.com"}).expect(http.StatusOK, nil)
+	// IP lain karena forgot/reset berbagi bucket auth-email (burst 3)
+	e.do(request{method: http.MethodPost, path: "/api/auth/password/forgot", remoteAddr: "198.51.100.7:1234",
+		body: map[string]string{"email": "nobody@example.com"}}).expect(http.StatusOK, nil)
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reset emails, want 1", len(sent))
+	}
+	// Access token session biasa tidak bisa dipakai untuk reset password
+	e.do(request{method: http.MethodPost, path: "/api/auth/password/reset", remoteAddr: "198.51.100.8:1234",
+		body: map[string]string{"access_token": session.AccessToken, "password": "rahasia9"}}).
+		expectError(http.StatusUnprocessableEntity, "validation_failed")
+	// Session yang sudah ada (misalnya milik penyerang) ikut dicabut saat reset
+	login("rahasia1").expect(http.StatusOK, &session)
+	reset := map[string]string{"token_hash": sent[0], "password": "rahasia2"}
+	e.send(http.MethodPost, "/api/auth/password/reset", "", reset).expect(http.StatusOK, nil)
+	e.send(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": session.RefreshToken}).
+		expectError(http.StatusUnauthorized, "invalid_refresh_token")
+	e.send(http.MethodPost, "/api/auth/password/reset", "", reset).expectError(http.StatusBadRequest, "invalid_reset_token")
+
+	login("rahasia1").expectError(http.StatusUnauthorized, "invalid_credentials")
+	login("rahasia2").expect(http.StatusOK, nil)
+
+	if err := e.local.DeleteUser(ctx, account.ID); err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+	if _, err := e.local.GetUser(ctx, account.ID); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("GetUser after delete: err = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestMemoryAuditLog(t *testing.T) {
+	e := newMemoryEnv(t)
+	mem := e.mem
+	admin := addMemoryUser(mem, "admin", "admin")
+	buyer := addMemoryUser(mem, "buyer", "user")
+
+	var created struct {
+		ProductID int32 `json:"product_id"`
+	}
+	body := map[string]any{"name": "Hoodie", "category": "Hoodie", "price": 150000, "stock": 5}
+	e.send(http.MethodPost, "/api/admin/products", admin.Token, body).expect(http.StatusOK, &created)
+	body["stock"] = 3
+	e.send(http.MethodPut, fmt.Sprintf("/api/admin/products/%d", created.ProductID), admin.Token, body).expect(http.StatusOK, nil)
+	order := mem.AddOrder(buyer.ID, created.ProductID, 1, "pending")
+	e.send(http.MethodPut, fmt.Sprintf("/api/admin/orders/%d/status", order), admin.Token, map[string]string{"status": "process"}).
+		expect(http.StatusOK, nil)
+	e.send(http.MethodPut, "/api/admin/customers/"+buyer.ID+"/role", admin.Token, map[string]string{"role": "admin"}).
+		expect(http.StatusOK, nil)
+	// Role yang sama: sukses tanpa audit log
+	e.send(http.MethodPut, "/api/admin/customers/"+buyer.ID+"/role", admin.Token, map[string]string{"role": "admin"}).
+		expect(http.StatusOK, nil)
+	// Gagal di tengah jalan: tidak ada audit log
+	e.send(http.MethodDelete, fmt.Sprintf("/api/admin/products/%d", created.ProductID), admin.Token, nil).
+		expectError(http.StatusConflict, "resource_in_use")
+
+	var page auditPage
+	e.get("/api/admin/audit", admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 4 || len(page.Data) != 4 {
+		t.Fatalf("audit log = %+v, want 4 entries", page)
+	}
+	wantActions := []string{service.AuditUserRole, service.AuditOrderStatus, service.AuditProductUpdate, service.AuditProductCreate}
+	for i, got := range page.Data {
+		if got.Action != wantActions[i] {
+			t.Errorf("entry %d action = %q, want %q", i, got.Action, wantActions[i])
+		}
+		if got.ActorID != admin.ID || got.RequestID == "" || got.IP != "192.0.2.1" {
+			t.Errorf("entry %d actor = %q request = %q ip = %q", i, got.ActorID, got.RequestID, got.IP)
+		}
+	}
+
+	// Update hanya mencatat field yang berubah
+	update := page.Data[2]
+	if len(update.Before) != 1 || update.Before["stock"] != float64(5) || update.After["stock"] != float64(3) {
+		t.Errorf("update diff = %v -> %v, want only stock 5 -> 3", update.Before, update.After)
+	}
+	if create := page.Data[3]; create.Before != nil || create.After["product_name"] != "Hoodie" {
+		t.Errorf("create diff = %v -> %v", create.Before, create.After)
+	}
+	if role := page.Data[0]; role.EntityID != buyer.ID || role.Before["role"] != "user" || role.After["role"] != "admin" {
+		t.Errorf("role entry = %+v", role)
+	}
+
+	e.get(fmt.Sprintf("/api/admin/audit?entity_type=product&entity_id=%d", created.ProductID), admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 2 {
+		t.Errorf("product entries = %d, want 2", page.Total)
+	}
+	e.get("/api/admin/audit?action=order.status&actor_id="+admin.ID, admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 1 || page.Data[0].Before["status"] != "pending" || page.Data[0].After["status"] != "process" {
+		t.Errorf("order status entries = %+v", page)
+	}
+	e.get("/api/admin/audit?from="+time.Now().Add(time.Hour).UTC().Format(time.RFC3339), admin.Token).expect(http.StatusOK, &page)
+	if page.Total != 0 || page.Data == nil {
+		t.Errorf("future entries = %+v, want empty list", page)
+	}
+
+	apiErr := e.get("/api/admin/audit?to=yesterday", admin.Token).expectError(http.StatusUnprocessableEntity, "validation_failed")
+	if len(apiErr.Details) != 1 || apiErr.Details[0].Field != "to" || apiErr.Details[0].Code != "datetime" {
+		t.Errorf("details = %+v", apiErr.Details)
+	}
+	apiErr = e.get("/api/admin/audit?actor_id=nope", admin.Token).expectError(http.StatusUnprocessableEntity, "validation_failed")
+	if len(apiErr.Details) != 1 || apiErr.Details[0].Field != "actor_id" {
+		t.Errorf("details = %+v", apiErr.Details)
+	}
+	e.get("/api/admin/audit", buyer.Token).expect(http.StatusOK, nil)
+	e.get("/api/admin/audit", addMemoryUser(mem, "other", "user").Token).expectError(http.StatusForbidden, "admin_required")
+}
@@ -2,67 +2,130 @@ package handler
 
 import (
 	"context"
+	"net"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
+
+	"backend/pkg/apierror"
+	"backend/pkg/database"
+	"backend/pkg/logging"
+	"backend/pkg/service"
+	"backend/pkg/tracing"
 )
 
-func (h *HttpServer) AdminOnly(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, "Unauthorized: No token provided", http.StatusUnauthorized)
-			return
-		}
+// bearerToken mengambil token dari header "Authorization: Bearer <token>".
+func bearerToken(r *http.Request) (string, error) {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return "", apierror.Unauthorized("No access token provided").WithKey("error.unauthorized.missing_token", nil)
+	}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Unauthorized: Invalid token format", http.StatusUnauthorized)
-			return
-		}
-		tokenString := parts[1]
-
-		jwtSecret := []byte(os.Getenv("SUPABASE_JWT_SECRET"))
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, http.ErrAbortHandler
-			}
-			return jwtSecret, nil
-		})
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "", apierror.Unauthorized("Authorization header must be \"Bearer <token>\"").WithKey("error.unauthorized.malformed_header", nil)
+	}
+	return parts[1], nil
+}
 
-		if err != nil || !token.Valid {
-			http.Error(w, "Unauthorized: Invalid token", http.StatusUnauthorized)
-			return
+// parseAccessToken memvalidasi access token Supabase dan mengembalikan claims-nya.
+func (h *HttpServer) parseAccessToken(r *http.Request) (jwt.MapClaims, error) {
+	tokenString, err := bearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtSecret := []byte(h.Config.Auth.JWTSecret)
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, http.ErrAbortHandler
 		}
+		return jwtSecret, nil
+	})
+
+	if err != nil || !token.Valid {
+		return nil, errUnauthorized.Wrap(err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errUnauthorized
+	}
+
+	if _, ok := claims["sub"].(string); !ok {
+		return nil, errUnauthorized
+	}
+	return claims, nil
+}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			http.Error(w, "Unauthorized: Invalid claims", http.StatusUnauthorized)
+func (h *HttpServer) AuthRequired(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := h.parseAccessToken(r)
+		if err != nil {
+			writeError(w, r, err)
 			return
 		}
 
-		userIDStr, ok := claims["sub"].(string)
-		if !ok {
-			http.Error(w, "Unauthorized: No user ID found in token", http.StatusUnauthorized)
+		userIDStr := claims["sub"].(string)
+		email, _ := claims["email"].(string)
+
+		logging.SetPrincipal(r.Context(), userIDStr)
+		ctx := database.WithClaims(r.Context(), claims)
+
+		// Access token tetap valid sampai expired, jadi akun yang sudah
+		// dinonaktifkan harus ditolak di sini
+		lookupCtx, span := tracing.Start(ctx, "AuthRequired status lookup")
+		err = h.Users.RequireActive(lookupCtx, userIDStr)
+		span.End()
+		if err != nil {
+			writeError(w, r, err)
 			return
 		}
+		ctx = context.WithValue(ctx, "userID", userIDStr)
+		ctx = context.WithValue(ctx, "email", email)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
 
-		var role string
-		err = h.DB.QueryRow(r.Context(), "SELECT role FROM users WHERE user_id = $1", userIDStr).Scan(&role)
-
+func (h *HttpServer) AdminOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := h.parseAccessToken(r)
 		if err != nil {
-			http.Error(w, "Forbidden: User not registered", http.StatusForbidden)
+			writeError(w, r, err)
 			return
 		}
 
-		if role != "admin" {
-			http.Error(w, "Forbidden: You are not admin!", http.StatusForbidden)
+		userIDStr := claims["sub"].(string)
+		logging.SetPrincipal(r.Context(), userIDStr)
+		// Lookup role juga lewat RLS, jadi claim harus sudah ada di context
+		ctx := database.WithClaims(r.Context(), claims)
+
+		lookupCtx, span := tracing.Start(ctx, "AdminOnly role lookup")
+		err = h.Users.RequireAdmin(lookupCtx, userIDStr)
+		span.End()
+		if err != nil {
+			writeError(w, r, err)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), "userID", userIDStr)
+		ctx = context.WithValue(ctx, "userID", userIDStr)
+		// Dicatat service di audit log bersama perubahan yang dilakukan admin
+		ctx = service.WithActor(ctx, service.Actor{
+			UserID:    userIDStr,
+			RequestID: logging.RequestID(ctx),
+			IP:        clientIP(r),
+		})
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
-}
\ No newline at end of file
+}
+
+// clientIP mengambil host dari RemoteAddr. Di belakang proxy RealIP sudah
+// mengisinya dengan IP asli (lihat RouterDeps.TrustProxy).
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,220 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type orderJSON struct {
+	OrderID           int32   `json:"order_id"`
+	Quantity          int32   `json:"quantity"`
+	Status            string  `json:"status"`
+	StatusLabel       string  `json:"status_label"`
+	CustomerName      string  `json:"customer_name"`
+	ProductName       string  `json:"product_name"`
+	ShipRecipientName *string `json:"ship_recipient_name"`
+	ShipCity          *string `json:"ship_city"`
+}
+
+type addressJSON struct {
+	AddressID     int32  `json:"address_id"`
+	Label         string `json:"label"`
+	RecipientName string `json:"recipient_name"`
+	City          string `json:"city"`
+	IsDefault     bool   `json:"is_default"`
+}
+
+func addressBody(label, city string) map[string]any {
+	return map[string]any{
+		"label":          label,
+		"recipient_name": "Penerima " + label,
+		"phone_number":   "08123456789",
+		"street":         "Jl. " + label,
+		"city":           city,
+		"post_code":      "40111",
+	}
+}
+
+func (e *env) createAddress(u testUser, label, city string) addressJSON {
+	e.t.Helper()
+	var a addressJSON
+	e.send(http.MethodPost, "/api/me/addresses", u.Token, addressBody(label, city)).expect(http.StatusCreated, &a)
+	return a
+}
+
+func (e *env) listOrders(token string, header map[string]string) []orderJSON {
+	e.t.Helper()
+	var orders []orderJSON
+	e.do(request{method: http.MethodGet, path: "/api/admin/orders", token: token, header: header}).expect(http.StatusOK, &orders)
+	return orders
+}
+
+func TestCreateOrder(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	buyer := e.createUser("buyer", userOpts{})
+	product := e.createProduct("Kaos Polos", 10)
+	e.createAddress(buyer, "Rumah", "Bandung")
+	office := e.createAddress(buyer, "Kantor", "Jakarta")
+
+	// Tanpa address_id: alamat default (alamat pertama) di-snapshot
+	e.send(http.MethodPost, "/api/admin/orders", admin.Token, map[string]any{
+		"user_id": buyer.ID, "product_id": product, "quantity": 2, "total_amount": 300000,
+	}).expect(http.StatusOK, nil)
+	e.send(http.MethodPost, "/api/admin/orders", admin.Token, map[string]any{
+		"user_id": buyer.ID, "product_id": product, "quantity": 1, "total_amount": 150000, "address_id": office.AddressID,
+	}).expect(http.StatusOK, nil)
+
+	cities := map[string]bool{}
+	for _, o := range e.listOrders(admin.Token, nil) {
+		if o.ShipCity == nil {
+			t.Fatalf("order %d has no shipping snapshot", o.OrderID)
+		}
+		cities[*o.ShipCity] = true
+		if o.Status != "pending" || o.CustomerName != "User buyer" {
+			t.Errorf("order = %+v", o)
+		}
+	}
+	if !cities["Bandung"] || !cities["Jakarta"] {
+		t.Errorf("shipping cities = %v, want Bandung and Jakarta", cities)
+	}
+
+	// Mengubah alamat tidak mengubah order yang sudah dibuat
+	e.send(http.MethodPut, fmt.Sprintf("/api/me/addresses/%d", office.AddressID), buyer.Token, addressBody("Kantor", "Surabaya")).
+		expect(http.StatusOK, nil)
+	if got := e.queryInt("SELECT COUNT(*) FROM orders WHERE ship_city = 'Jakarta'"); got != 1 {
+		t.Errorf("snapshot changed with address: %d orders still in Jakarta", got)
+	}
+
+	// Buku alamat kosong: snapshot diambil dari alamat di profil
+	legacy := e.createUser("legacy", userOpts{})
+	e.send(http.MethodPost, "/api/admin/orders", admin.Token, map[string]any{
+		"user_id": legacy.ID, "product_id": product, "quantity": 1, "total_amount": 150000,
+	}).expect(http.StatusOK, nil)
+	if got := e.queryInt("SELECT COUNT(*) FROM orders WHERE user_id = $1 AND address_id IS NULL AND ship_street = 'Jl. Merdeka 1'", legacy.ID); got != 1 {
+		t.Errorf("profile fallback orders = %d, want 1", got)
+	}
+}
+
+func TestCreateOrderRejections(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	unverified := e.createUser("unverified", userOpts{unverified: true})
+	disabled := e.createUser("disabled", userOpts{disabled: true})
+	buyer := e.createUser("buyer", userOpts{})
+	other := e.createUser("other", userOpts{})
+	otherAddress := e.createAddress(other, "Rumah", "Medan")
+	product := e.createProduct("Kaos Polos", 10)
+
+	order := func(userID string, extra map[string]any) *response {
+		body := map[string]any{"user_id": userID, "product_id": product, "quantity": 1, "total_amount": 150000}
+		for k, v := range extra {
+			body[k] = v
+		}
+		return e.send(http.MethodPost, "/api/admin/orders", admin.Token, body)
+	}
+
+	order(unverified.ID, nil).expectError(http.StatusForbidden, "email_not_verified")
+	order(disabled.ID, nil).expectError(http.StatusForbidden, "account_disabled")
+	order("00000000-0000-0000-0000-000000000001", nil).expectError(http.StatusNotFound, "user_not_found")
+	order(buyer.ID, map[string]any{"address_id": otherAddress.AddressID}).expectError(http.StatusNotFound, "address_not_found")
+	order(buyer.ID, map[string]any{"product_id": 9999}).expectError(http.StatusUnprocessableEntity, "invalid_reference")
+	order("not-a-uuid", nil).expectError(http.StatusUnprocessableEntity, "validation_failed")
+	order(buyer.ID, map[string]any{"quantity": 0}).expectError(http.StatusUnprocessableEntity, "validation_failed")
+	order(buyer.ID, map[string]any{"status": "shipped"}).expectError(http.StatusUnprocessableEntity, "validation_failed")
+
+	if got := e.queryInt("SELECT COUNT(*) FROM orders"); got != 0 {
+		t.Errorf("orders = %d, want 0", got)
+	}
+}
+
+func TestListOrdersLocalized(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	buyer := e.createUser("buyer", userOpts{})
+	e.createOrder(buyer.ID, e.createProduct("Kaos Polos", 10), 1, "process")
+
+	if got := e.listOrders(admin.Token, nil); len(got) != 1 || got[0].StatusLabel != "Processing" {
+		t.Errorf("en orders = %+v", got)
+	}
+	if got := e.listOrders(admin.Token, map[string]string{"Accept-Language": "id-ID,id;q=0.9"}); len(got) != 1 || got[0].StatusLabel != "Diproses" || got[0].Status != "process" {
+		t.Errorf("id orders = %+v", got)
+	}
+}
+
+func TestUpdateOrderStatusDecreasesStock(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	buyer := e.createUser("buyer", userOpts{})
+	product := e.createProduct("Kaos Polos", 5)
+	order := e.createOrder(buyer.ID, product, 3, "pending")
+	path := fmt.Sprintf("/api/admin/orders/%d/status", order)
+
+	e.send(http.MethodPut, path, admin.Token, map[string]string{"status": "process"}).expect(http.StatusOK, nil)
+	if got := e.queryInt("SELECT stock FROM products WHERE product_id = $1", product); got != 5 {
+		t.Errorf("stock after process = %d, want 5", got)
+	}
+
+	var res struct {
+		Status       string `json:"status"`
+		Notification struct {
+			Title string `json:"title"`
+			Body  string `json:"body"`
+		} `json:"notification"`
+	}
+	e.do(request{method: http.MethodPut, path: path, token: admin.Token, body: map[string]string{"status": "done"},
+		header: map[string]string{"Accept-Language": "id"}}).expect(http.StatusOK, &res)
+	if res.Status != "updated" || res.Notification.Body != fmt.Sprintf("Pesanan #%d Anda sekarang Selesai.", order) {
+		t.Errorf("response = %+v", res)
+	}
+
+	if got := e.queryInt("SELECT stock FROM products WHERE product_id = $1", product); got != 2 {
+		t.Errorf("stock after done = %d, want 2", got)
+	}
+
+	// Mengulang "done" tidak mengurangi stok lagi dan tidak menambah audit log
+	audits := e.queryInt("SELECT COUNT(*) FROM audit_log")
+	e.send(http.MethodPut, path, admin.Token, map[string]string{"status": "done"}).expect(http.StatusOK, nil)
+	if got := e.queryInt("SELECT stock FROM products WHERE product_id = $1", product); got != 2 {
+		t.Errorf("stock after repeated done = %d, want 2", got)
+	}
+	if got := e.queryInt("SELECT COUNT(*) FROM audit_log"); got != audits {
+		t.Errorf("audit rows after repeated done = %d, want %d", got, audits)
+	}
+}
+
+func TestUpdateOrderStatusInsufficientStockRollsBack(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	buyer := e.createUser("buyer", userOpts{})
+	product := e.createProduct("Kaos Polos", 2)
+	order := e.createOrder(buyer.ID, product, 3, "process")
+
+	e.send(http.MethodPut, fmt.Sprintf("/api/admin/orders/%d/status", order), admin.Token, map[string]string{"status": "done"}).
+		expectError(http.StatusConflict, "insufficient_stock")
+
+	// Status dan stok kembali seperti sebelum request karena satu transaksi
+	var status string
+	testDB.QueryRow(t.Context(), "SELECT status FROM orders WHERE order_id = $1", order).Scan(&status)
+	if status != "process" {
+		t.Errorf("status = %q, want process", status)
+	}
+	if got := e.queryInt("SELECT stock FROM products WHERE product_id = $1", product); got != 2 {
+		t.Errorf("stock = %d, want 2", got)
+	}
+}
+
+func TestUpdateOrderStatusRejections(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	buyer := e.createUser("buyer", userOpts{})
+	order := e.createOrder(buyer.ID, e.createProduct("Kaos Polos", 2), 1, "pending")
+
+	e.send(http.MethodPut, "/api/admin/orders/9999/status", admin.Token, map[string]string{"status": "done"}).
+		expectError(http.StatusNotFound, "order_not_found")
+	e.send(http.MethodPut, fmt.Sprintf("/api/admin/orders/%d/status", order), admin.Token, map[string]string{"status": "shipped"}).
+		expectError(http.StatusUnprocessableEntity, "validation_failed")
+	e.send(http.MethodPut, "/api/admin/orders/0/status", admin.Token, map[string]string{"status": "done"}).
+		expectError(http.StatusBadRequest, "invalid_id")
+}
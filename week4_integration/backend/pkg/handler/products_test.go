@@ -0,0 +1,103 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+type productJSON struct {
+	ProductID   int32  `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Category    string `json:"category"`
+	Stock       int32  `json:"stock"`
+}
+
+func TestPublicProducts(t *testing.T) {
+	e := newEnv(t)
+	inStock := e.createProduct("Hoodie Hitam", 5)
+	e.createProduct("Hoodie Putih", 0)
+
+	var list []productJSON
+	e.get("/api/products", "").expect(http.StatusOK, &list)
+	if len(list) != 1 || list[0].ProductID != inStock {
+		t.Errorf("public list = %+v, want only product %d", list, inStock)
+	}
+
+	var p productJSON
+	e.get(fmt.Sprintf("/api/products/%d", inStock), "").expect(http.StatusOK, &p)
+	if p.ProductName != "Hoodie Hitam" || p.Stock != 5 {
+		t.Errorf("detail = %+v", p)
+	}
+
+	e.get("/api/products/9999", "").expectError(http.StatusNotFound, "product_not_found")
+	e.get("/api/products/abc", "").expectError(http.StatusBadRequest, "invalid_id")
+}
+
+func TestAdminProducts(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+
+	body := map[string]any{
+		"name":        "Jaket Denim",
+		"category":    "Jacket",
+		"description": "Jaket denim biru",
+		"price":       350000,
+		"image_url":   "https://example.com/jaket.png",
+		"stock":       0,
+	}
+	var created struct {
+		ProductID int32 `json:"product_id"`
+	}
+	e.send(http.MethodPost, "/api/admin/products", admin.Token, body).expect(http.StatusOK, &created)
+	if created.ProductID == 0 {
+		t.Fatal("no product_id returned")
+	}
+
+	// Admin melihat produk dengan stok 0, publik tidak
+	var all []productJSON
+	e.get("/api/admin/products", admin.Token).expect(http.StatusOK, &all)
+	if len(all) != 1 || all[0].Stock != 0 {
+		t.Errorf("admin list = %+v", all)
+	}
+
+	body["stock"] = 12
+	body["name"] = "Jaket Denim Baru"
+	path := fmt.Sprintf("/api/admin/products/%d", created.ProductID)
+	e.send(http.MethodPut, path, admin.Token, body).expect(http.StatusOK, nil)
+	if got := e.queryInt("SELECT stock FROM products WHERE product_id = $1", created.ProductID); got != 12 {
+		t.Errorf("stock after update = %d, want 12", got)
+	}
+
+	body["price"] = 0
+	e.send(http.MethodPost, "/api/admin/products", admin.Token, body).expectError(http.StatusUnprocessableEntity, "validation_failed")
+	e.send(http.MethodPost, "/api/admin/products", admin.Token, `{"name":"x","unknown":1}`).
+		expectError(http.StatusUnprocessableEntity, "validation_failed")
+
+	e.send(http.MethodDelete, path, admin.Token, nil).expect(http.StatusOK, nil)
+	if got := e.queryInt("SELECT COUNT(*) FROM products"); got != 0 {
+		t.Errorf("products after delete = %d", got)
+	}
+}
+
+func TestDeleteProductInUse(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	buyer := e.createUser("buyer", userOpts{})
+	product := e.createProduct("Kaos Polos", 10)
+	e.createOrder(buyer.ID, product, 1, "pending")
+
+	e.send(http.MethodDelete, fmt.Sprintf("/api/admin/products/%d", product), admin.Token, nil).
+		expectError(http.StatusConflict, "resource_in_use")
+}
+
+func TestUpdateDeleteMissingProduct(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+
+	e.send(http.MethodPut, "/api/admin/products/9999", admin.Token, map[string]any{
+		"name": "Hoodie", "category": "Hoodie", "price": 150000, "stock": 5,
+	}).expectError(http.StatusNotFound, "product_not_found")
+	e.send(http.MethodDelete, "/api/admin/products/9999", admin.Token, nil).
+		expectError(http.StatusNotFound, "product_not_found")
+}
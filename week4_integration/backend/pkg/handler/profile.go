@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"backend/pkg/service"
+)
+
+type meResponse struct {
+	userResponse
+	Addresses []service.Address `json:"addresses"`
+}
+
+type addressRequest struct {
+	Label         string `json:"label" validate:"required,max=50"`
+	RecipientName string `json:"recipient_name" validate:"required,max=100"`
+	PhoneNumber   string `json:"phone_number" validate:"required,max=20"`
+	Street        string `json:"street" validate:"required,max=100"`
+	City          string `json:"city" validate:"required,max=50"`
+	PostCode      string `json:"post_code" validate:"required,max=10"`
+	IsDefault     bool   `json:"is_default"`
+}
+
+func (req addressRequest) input() service.AddressInput {
+	return service.AddressInput{
+		Label:         req.Label,
+		RecipientName: req.RecipientName,
+		PhoneNumber:   req.PhoneNumber,
+		Street:        req.Street,
+		City:          req.City,
+		PostCode:      req.PostCode,
+		IsDefault:     req.IsDefault,
+	}
+}
+
+// currentUserID membaca user ID yang sudah diset AuthRequired.
+func currentUserID(r *http.Request) (string, error) {
+	userID, _ := r.Context().Value("userID").(string)
+	if userID == "" {
+		return "", errUnauthorized
+	}
+	return userID, nil
+}
+
+// Profile
+
+func (h *HttpServer) HandleGetMe(w http.ResponseWriter, r *http.Request) {
+	userID, _ := r.Context().Value("userID").(string)
+	email, _ := r.Context().Value("email").(string)
+
+	user, err := h.loadUser(r, userID, email)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	addresses, err := h.Users.Addresses(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, meResponse{userResponse: user, Addresses: addresses})
+}
+
+func (h *HttpServer) HandleUpdateMe(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Username    *string `json:"username" validate:"min=1,max=50"`
+		FullName    *string `json:"full_name" validate:"min=1,max=100"`
+		PhoneNumber *string `json:"phone_number" validate:"min=1,max=20"`
+	}
+
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	profile, err := h.Users.UpdateProfile(r.Context(), userID, service.ProfileUpdate{
+		Username:    req.Username,
+		FullName:    req.FullName,
+		PhoneNumber: req.PhoneNumber,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	email, _ := r.Context().Value("email").(string)
+	writeJSON(w, userResponse{
+		ID:          userID,
+		Email:       email,
+		Username:    profile.Username,
+		FullName:    profile.FullName,
+		PhoneNumber: profile.PhoneNumber,
+		Role:        profile.Role,
+		Street:      profile.Street,
+		City:        profile.City,
+		PostCode:    profile.PostCode,
+	})
+}
+
+// Address book
+
+func (h *HttpServer) HandleListAddresses(w http.ResponseWriter, r *http.Request) {
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	addresses, err := h.Users.Addresses(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, addresses)
+}
+
+func (h *HttpServer) HandleCreateAddress(w http.ResponseWriter, r *http.Request) {
+	var req addressRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	address, err := h.Users.CreateAddress(r.Context(), userID, req.input())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(address)
+}
+
+func (h *HttpServer) HandleUpdateAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req addressRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	address, err := h.Users.UpdateAddress(r.Context(), userID, id, req.input())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, address)
+}
+
+func (h *HttpServer) HandleDeleteAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.Users.DeleteAddress(r.Context(), userID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "deleted"})
+}
+
+func (h *HttpServer) HandleSetDefaultAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	userID, err := currentUserID(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.Users.SetDefaultAddress(r.Context(), userID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, map[string]string{"status": "updated"})
+}
@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestProfile(t *testing.T) {
+	e := newEnv(t)
+	u := e.createUser("maya", userOpts{})
+	e.createUser("taken", userOpts{})
+
+	var me struct {
+		ID        string        `json:"id"`
+		Username  string        `json:"username"`
+		FullName  string        `json:"full_name"`
+		Addresses []addressJSON `json:"addresses"`
+	}
+	e.get("/api/me", u.Token).expect(http.StatusOK, &me)
+	if me.ID != u.ID || me.Username != "maya" || me.Addresses == nil || len(me.Addresses) != 0 {
+		t.Errorf("me = %+v", me)
+	}
+
+	// PATCH hanya mengubah field yang dikirim
+	e.send(http.MethodPatch, "/api/me", u.Token, map[string]string{"full_name": "Maya Sari"}).expect(http.StatusOK, &me)
+	if me.FullName != "Maya Sari" || me.Username != "maya" {
+		t.Errorf("after patch = %+v", me)
+	}
+
+	e.send(http.MethodPatch, "/api/me", u.Token, map[string]string{"username": "taken"}).
+		expectError(http.StatusConflict, "already_exists")
+	e.send(http.MethodPatch, "/api/me", u.Token, map[string]string{"username": ""}).
+		expectError(http.StatusUnprocessableEntity, "validation_failed")
+
+	e.get("/api/me", "").expectError(http.StatusUnauthorized, "unauthorized")
+	noProfile := e.createUser("ghost", userOpts{noProfile: true})
+	e.get("/api/me", noProfile.Token).expectError(http.StatusForbidden, "user_not_registered")
+}
+
+func TestAddresses(t *testing.T) {
+	e := newEnv(t)
+	u := e.createUser("maya", userOpts{})
+	other := e.createUser("other", userOpts{})
+
+	home := e.createAddress(u, "Rumah", "Bandung")
+	if !home.IsDefault {
+		t.Errorf("first address should become default: %+v", home)
+	}
+	office := e.createAddress(u, "Kantor", "Jakarta")
+	if office.IsDefault {
+		t.Errorf("second address should not be default: %+v", office)
+	}
+
+	e.send(http.MethodPut, fmt.Sprintf("/api/me/addresses/%d/default", office.AddressID), u.Token, nil).expect(http.StatusOK, nil)
+
+	var list []addressJSON
+	e.get("/api/me/addresses", u.Token).expect(http.StatusOK, &list)
+	defaults := 0
+	for _, a := range list {
+		if a.IsDefault {
+			defaults++
+			if a.AddressID != office.AddressID {
+				t.Errorf("default = %d, want %d", a.AddressID, office.AddressID)
+			}
+		}
+	}
+	if len(list) != 2 || defaults != 1 {
+		t.Errorf("addresses = %+v", list)
+	}
+
+	var updated addressJSON
+	e.send(http.MethodPut, fmt.Sprintf("/api/me/addresses/%d", home.AddressID), u.Token, addressBody("Rumah", "Cimahi")).
+		expect(http.StatusOK, &updated)
+	if updated.City != "Cimahi" {
+		t.Errorf("updated = %+v", updated)
+	}
+
+	// Alamat milik user lain tidak terlihat sama sekali
+	otherPath := fmt.Sprintf("/api/me/addresses/%d", home.AddressID)
+	e.send(http.MethodPut, otherPath, other.Token, addressBody("Rumah", "Medan")).expectError(http.StatusNotFound, "address_not_found")
+	e.send(http.MethodPut, otherPath+"/default", other.Token, nil).expectError(http.StatusNotFound, "address_not_found")
+	e.send(http.MethodDelete, otherPath, other.Token, nil).expectError(http.StatusNotFound, "address_not_found")
+
+	e.send(http.MethodPost, "/api/me/addresses", u.Token, map[string]any{"label": "Kosong"}).
+		expectError(http.StatusUnprocessableEntity, "validation_failed")
+	e.send(http.MethodDelete, "/api/me/addresses/abc", u.Token, nil).expectError(http.StatusBadRequest, "invalid_id")
+
+	e.send(http.MethodDelete, fmt.Sprintf("/api/me/addresses/%d", home.AddressID), u.Token, nil).expect(http.StatusOK, nil)
+	if got := e.queryInt("SELECT COUNT(*) FROM addresses WHERE user_id = $1", u.ID); got != 1 {
+		t.Errorf("addresses after delete = %d, want 1", got)
+	}
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"backend/pkg/apierror"
+	"backend/pkg/validate"
+)
+
+// maxBodyBytes membatasi body JSON; payload terbesar (produk dengan deskripsi)
+// jauh di bawah ini.
+const maxBodyBytes = 64 << 10
+
+// decodeJSON membaca body secara ketat (field tak dikenal ditolak, ukuran
+// dibatasi, tidak boleh ada data sisa) lalu menjalankan validasi tag
+// `validate`. Error yang dikembalikan sudah berupa *apierror.Error.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return apierror.BadRequest(apierror.CodeInvalidJSON, "Request body must contain a single JSON object").
+			WithKey("error.invalid_json.trailing", nil)
+	}
+
+	if details := validate.Struct(dst); len(details) > 0 {
+		return apierror.Validation(details...)
+	}
+	return nil
+}
+
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxErr *http.MaxBytesError
+
+	switch {
+	case errors.Is(err, io.EOF):
+		return apierror.BadRequest(apierror.CodeInvalidJSON, "Request body is empty").WithKey("error.invalid_json.empty", nil)
+	case errors.As(err, &maxErr):
+		return apierror.New(http.StatusRequestEntityTooLarge, "request_too_large",
+			fmt.Sprintf("Request body must not exceed %d bytes", maxErr.Limit)).
+			WithKey("error.request_too_large", map[string]string{"limit": strconv.FormatInt(maxErr.Limit, 10)})
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return errInvalidJSON.Wrap(err)
+	case errors.As(err, &typeErr):
+		typ := jsonType(typeErr.Type.Kind().String())
+		return apierror.Validation(apierror.FieldError{
+			Field:   typeErr.Field,
+			Code:    "type",
+			Message: "must be a " + typ,
+			Key:     "validation.type." + typ,
+		})
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return apierror.Validation(apierror.FieldError{Field: field, Code: "unknown_field", Message: "is not a recognized field"})
+	}
+	return errInvalidJSON.Wrap(err)
+}
+
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "number"
+	case kind == "bool":
+		return "boolean"
+	case kind == "slice", kind == "array":
+		return "list"
+	case kind == "struct", kind == "map":
+		return "object"
+	}
+	return kind
+}
+
+// pathID membaca parameter path integer positif, mis. {id} di /api/products/{id}.
+func pathID(r *http.Request, name string) (int32, error) {
+	raw := chi.URLParam(r, name)
+	id, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || id < 1 {
+		return 0, apierror.BadRequest(apierror.CodeInvalidID, name+" must be a positive integer").
+			WithKey("error.invalid_id", map[string]string{"param": name})
+	}
+	return int32(id), nil
+}
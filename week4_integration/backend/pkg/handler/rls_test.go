@@ -0,0 +1,117 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"backend/pkg/database"
+)
+
+// scopedInt menjalankan query di bawah role dan claim user seperti backend.
+// userID kosong berarti request anonim.
+func scopedInt(t *testing.T, userID, sql string, args ...any) int64 {
+	t.Helper()
+	ctx := context.Background()
+	if userID != "" {
+		ctx = database.WithClaims(ctx, map[string]any{"sub": userID, "role": "authenticated"})
+	}
+	var n int64
+	err := database.Scoped(ctx, testDB, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, sql, args...).Scan(&n)
+	})
+	if err != nil {
+		t.Fatalf("%s: %v", sql, err)
+	}
+	return n
+}
+
+func TestRLSPolicies(t *testing.T) {
+	e := newEnv(t)
+	admin := e.createUser("admin", userOpts{role: "admin"})
+	budi := e.createUser("budi", userOpts{})
+	sari := e.createUser("sari", userOpts{})
+	inStock := e.createProduct("Hoodie Hitam", 5)
+	soldOut := e.createProduct("Hoodie Putih", 0)
+	e.createOrder(budi.ID, inStock, 1, "pending")
+	e.createOrder(sari.ID, inStock, 2, "pending")
+
+	tests := []struct {
+		name   string
+		userID string
+		sql    string
+		want   int64
+	}{
+		{"anon sees in-stock products", "", "SELECT COUNT(*) FROM products", 1},
+		{"anon sees no users", "", "SELECT COUNT(*) FROM users", 0},
+		{"anon sees no orders", "", "SELECT COUNT(*) FROM orders", 0},
+		{"user sees in-stock products", budi.ID, "SELECT COUNT(*) FROM products", 1},
+		{"user sees own profile only", budi.ID, "SELECT COUNT(*) FROM users", 1},
+		{"user sees own orders only", budi.ID, "SELECT COUNT(*) FROM orders", 1},
+		{"admin sees all products", admin.ID, "SELECT COUNT(*) FROM products", 2},
+		{"admin sees all users", admin.ID, "SELECT COUNT(*) FROM users", 3},
+		{"admin sees all orders", admin.ID, "SELECT COUNT(*) FROM orders", 2},
+	}
+	for _, tt := range tests {
+		if got := scopedInt(t, tt.userID, tt.sql); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// Update di luar policy tidak error, hanya tidak mengenai baris apa pun
+	ctx := database.WithClaims(context.Background(), map[string]any{"sub": budi.ID, "role": "authenticated"})
+	err := database.Scoped(ctx, testDB, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "UPDATE users SET full_name = 'x' WHERE user_id = $1", sari.ID)
+		if err == nil && tag.RowsAffected() != 0 {
+			return fmt.Errorf("updated %d rows of another user", tag.RowsAffected())
+		}
+		return err
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// User tidak bisa menjadikan dirinya admin
+	err = database.Scoped(ctx, testDB, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "UPDATE users SET role = 'admin' WHERE user_id = $1", budi.ID)
+		return err
+	})
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "42501" {
+		t.Errorf("self promotion: err = %v, want insufficient_privilege", err)
+	}
+
+	// Akun yang dinonaktifkan tidak bisa mengubah profil maupun alamatnya
+	e.createAddress(sari, "Rumah", "Bandung")
+	if _, err := testDB.Exec(context.Background(), "UPDATE users SET disabled_at = NOW() WHERE user_id = $1", sari.ID); err != nil {
+		t.Fatal(err)
+	}
+	sariCtx := database.WithClaims(context.Background(), map[string]any{"sub": sari.ID, "role": "authenticated"})
+	err = database.Scoped(sariCtx, testDB, func(tx pgx.Tx) error {
+		for _, sql := range []string{
+			"UPDATE users SET full_name = 'x' WHERE user_id = $1",
+			"UPDATE addresses SET label = 'x' WHERE user_id = $1",
+		} {
+			tag, err := tx.Exec(sariCtx, sql, sari.ID)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() != 0 {
+				return fmt.Errorf("%s: disabled user updated %d rows", sql, tag.RowsAffected())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	e.get("/api/me/addresses", sari.Token).expectError(http.StatusForbidden, "account_disabled")
+
+	// Produk stok 0 tidak terlihat publik, termasuk lewat API
+	e.get(fmt.Sprintf("/api/products/%d", soldOut), "").expectError(http.StatusNotFound, "product_not_found")
+}
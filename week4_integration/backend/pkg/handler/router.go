@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"backend/pkg/apierror"
+	"backend/pkg/auth"
+	"backend/pkg/config"
+	"backend/pkg/i18n"
+	"backend/pkg/logging"
+	"backend/pkg/metrics"
+	"backend/pkg/ratelimit"
+	"backend/pkg/service"
+	"backend/pkg/tracing"
+)
+
+// RouterDeps berisi semua dependency yang dibutuhkan route table.
+type RouterDeps struct {
+	Config         *config.Config
+	DB             *pgxpool.Pool
+	Auth           auth.Provider
+	RateLimitStore ratelimit.Store
+
+	// Services kosong berarti service Postgres di atas DB. Test bisa mengisi
+	// service.NewMemory().Services() supaya tidak butuh database.
+	Services service.Services
+
+	// TrustProxy mengambil IP client dari X-Forwarded-For/X-Real-IP.
+	// Aktifkan hanya di belakang proxy tepercaya (mis. Vercel).
+	TrustProxy bool
+}
+
+// NewRouter membangun route table yang dipakai cmd/main.go dan api/index.go,
+// jadi endpoint baru cukup didaftarkan di sini.
+func NewRouter(deps RouterDeps) http.Handler {
+	svc := deps.Services
+	if svc.Products == nil {
+		svc = service.NewPostgres(deps.DB)
+	}
+	h := NewHttpServer(deps.Config, deps.DB, svc, deps.Auth)
+
+	limit := func(p ratelimit.Policy) func(http.Handler) http.Handler {
+		return ratelimit.Middleware(deps.RateLimitStore, p, ratelimit.ByUserOrIP)
+	}
+	// Route yang butuh login dibatasi per IP dulu, sebelum AuthRequired/AdminOnly
+	// (yang cek ke database), lalu per user setelah token valid
+	limitIP := ratelimit.Middleware(deps.RateLimitStore, ratelimit.AuthIPPolicy, ratelimit.ByIP)
+
+	r := chi.NewRouter()
+	r.Use(logging.RequestIDMiddleware)
+	r.Use(i18n.Middleware)
+	if deps.TrustProxy {
+		r.Use(middleware.RealIP)
+	}
+	r.Use(tracing.Middleware)
+	r.Use(logging.AccessLog)
+	if deps.Config.Metrics.Enabled {
+		r.Use(metrics.Middleware)
+	}
+	r.Use(CORS(NewCORSConfig(deps.Config.CORS)))
+
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, apierror.NotFound(apierror.CodeNotFound, "Route not found").WithKey("error.not_found.route", nil))
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, apierror.New(http.StatusMethodNotAllowed, "method_not_allowed", "Method not allowed"))
+	})
+
+	// Probe untuk Vercel/load balancer/uptime check, sengaja tanpa rate limit
+	r.Get("/healthz", HandleHealthz)
+	r.Get("/readyz", h.HandleReadyz)
+	r.Get("/version", h.HandleVersion)
+	if deps.Config.Metrics.Enabled {
+		if deps.DB != nil {
+			if err := metrics.RegisterPool(deps.DB); err != nil {
+				slog.Warn("register db pool metrics", logging.Err(err))
+			}
+		}
+		r.Method(http.MethodGet, "/metrics", metrics.Handler(deps.Config.Metrics.Token))
+	}
+
+	r.With(limit(ratelimit.RegisterPolicy)).Post("/api/auth/register", h.HandleRegister)
+	r.With(limit(ratelimit.LoginPolicy)).Post("/api/auth/login", h.HandleLogin)
+	r.With(limit(ratelimit.LoginPolicy)).Post("/api/auth/refresh", h.HandleRefresh)
+	r.With(limit(ratelimit.PublicPolicy)).Post("/api/auth/logout", h.HandleLogout)
+	r.With(limitIP, h.AuthRequired, limit(ratelimit.UserPolicy)).Get("/api/auth/me", h.HandleMe)
+	r.With(limit(ratelimit.AuthEmailPolicy)).Post("/api/auth/password/forgot", h.HandleForgotPassword)
+	r.With(limit(ratelimit.AuthEmailPolicy)).Post("/api/auth/password/reset", h.HandleResetPassword)
+	r.With(limit(ratelimit.AuthEmailPolicy)).Post("/api/auth/verify/resend", h.HandleResendVerification)
+
+	r.With(limit(ratelimit.PublicPolicy)).Get("/api/products", h.HandleListPublicProducts)
+	r.With(limit(ratelimit.PublicPolicy)).Get("/api/products/{id}", h.HandleGetProductDetail)
+
+	r.Route("/api/me", func(r chi.Router) {
+		r.Use(limitIP)
+		r.Use(h.AuthRequired)
+		r.Use(limit(ratelimit.UserPolicy))
+
+		r.Get("/", h.HandleGetMe)
+		r.Patch("/", h.HandleUpdateMe)
+
+		r.Get("/addresses", h.HandleListAddresses)
+		r.Post("/addresses", h.HandleCreateAddress)
+		r.Put("/addresses/{id}", h.HandleUpdateAddress)
+		r.Delete("/addresses/{id}", h.HandleDeleteAddress)
+		r.Put("/addresses/{id}/default", h.HandleSetDefaultAddress)
+	})
+
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Use(limitIP)
+		r.Use(h.AdminOnly)
+		r.Use(limit(ratelimit.UserPolicy))
+
+		r.Get("/products", h.HandleAdminListProducts)
+		r.Post("/products", h.HandleCreateProduct)
+		r.Put("/products/{id}", h.HandleUpdateProduct)
+		r.Delete("/products/{id}", h.HandleDeleteProduct)
+
+		r.Get("/orders", h.HandleListOrders)
+		r.Post("/orders", h.HandleCreateOrder)
+		r.Put("/orders/{id}/status", h.HandleUpdateOrderStatus)
+
+		r.Get("/customers", h.HandleListCustomers)
+		r.Get("/customers/{id}", h.HandleGetCustomer)
+		r.Put("/customers/{id}/role", h.HandleUpdateCustomerRole)
+		r.Delete("/customers/{id}", h.HandleDisableCustomer)
+		r.Post("/customers/{id}/enable", h.HandleEnableCustomer)
+
+		r.Get("/audit", h.HandleListAuditLog)
+	})
+
+	return r
+}
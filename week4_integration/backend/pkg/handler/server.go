@@ -1,34 +1,72 @@
 package handler
 
 import (
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/nedpals/supabase-go"
 
-	"backend/pkg/app/admindb"
-	"backend/pkg/app/publicdb"
+	"backend/pkg/apierror"
+	"backend/pkg/auth"
+	"backend/pkg/config"
+	"backend/pkg/i18n"
+	"backend/pkg/metrics"
+	"backend/pkg/service"
 )
 
+// productRequest dipakai untuk create dan update (PUT mengganti semua field).
+type productRequest struct {
+	Name        string  `json:"name" validate:"required,max=100"`
+	Category    string  `json:"category" validate:"required,max=50"`
+	Description string  `json:"description" validate:"max=5000"`
+	Price       float64 `json:"price" validate:"gt=0,max=1000000000"`
+	ImageUrl    string  `json:"image_url" validate:"max=2048"`
+	Stock       int32   `json:"stock" validate:"min=0,max=1000000"`
+}
+
+func (req productRequest) input() service.ProductInput {
+	return service.ProductInput{
+		Name:        req.Name,
+		Category:    req.Category,
+		Description: req.Description,
+		Price:       req.Price,
+		ImageUrl:    req.ImageUrl,
+		Stock:       req.Stock,
+	}
+}
+
+// productSummary adalah bentuk produk di katalog publik (tanpa description).
+type productSummary struct {
+	ProductID   int32          `json:"product_id"`
+	ImageUrl    string         `json:"image_url"`
+	ProductName string         `json:"product_name"`
+	Stock       int32          `json:"stock"`
+	UnitPrice   pgtype.Numeric `json:"unit_price"`
+	Category    string         `json:"category"`
+}
+
 type HttpServer struct {
-	DB             *pgxpool.Pool
-	PublicQ        *publicdb.Queries
-	AdminQ         *admindb.Queries
-	SupabaseClient *supabase.Client
+	Config *config.Config
+	// DB hanya dipakai probe /readyz dan /version; data lewat service.
+	DB       *pgxpool.Pool
+	Products service.ProductService
+	Orders   service.OrderService
+	Users    service.UserService
+	Audit    service.AuditService
+	Auth     auth.Provider
 }
 
-func NewHttpServer(db *pgxpool.Pool, sb *supabase.Client) *HttpServer {
+func NewHttpServer(cfg *config.Config, db *pgxpool.Pool, svc service.Services, ap auth.Provider) *HttpServer {
 	return &HttpServer{
-		DB:             db,
-		PublicQ:        publicdb.New(db),
-		AdminQ:         admindb.New(db),
-		SupabaseClient: sb,
+		Config:   cfg,
+		DB:       db,
+		Products: svc.Products,
+		Orders:   svc.Orders,
+		Users:    svc.Users,
+		Audit:    svc.Audit,
+		Auth:     ap,
 	}
 }
 
@@ -41,25 +79,25 @@ func writeJSON(w http.ResponseWriter, data interface{}) {
 
 func (h *HttpServer) HandleRegister(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Email       string `json:"email"`
-		Password    string `json:"password"`
-		Username    string `json:"username"`
-		FullName    string `json:"full_name"`
-		PhoneNumber string `json:"phone_number"`
-		Street      string `json:"street"`
-		City        string `json:"city"`
-		PostCode    string `json:"post_code"`
+		Email       string `json:"email" validate:"required,email,max=255"`
+		Password    string `json:"password" validate:"required,min=6,max=72"`
+		Username    string `json:"username" validate:"required,max=50"`
+		FullName    string `json:"full_name" validate:"required,max=100"`
+		PhoneNumber string `json:"phone_number" validate:"required,max=20"`
+		Street      string `json:"street" validate:"required,max=100"`
+		City        string `json:"city" validate:"required,max=50"`
+		PostCode    string `json:"post_code" validate:"required,max=10"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid input", 400)
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	_, err := h.SupabaseClient.Auth.SignUp(r.Context(), supabase.UserCredentials{
+	_, err := h.Auth.SignUp(r.Context(), auth.SignUpParams{
 		Email:    req.Email,
 		Password: req.Password,
-		Data: map[string]interface{}{
+		Data: map[string]any{
 			"username":     req.Username,
 			"full_name":    req.FullName,
 			"phone_number": req.PhoneNumber,
@@ -70,33 +108,45 @@ func (h *HttpServer) HandleRegister(w http.ResponseWriter, r *http.Request) {
 	})
 
 	if err != nil {
-		http.Error(w, "Gagal mendaftar: "+err.Error(), 500)
+		writeError(w, r, apierror.BadRequest("registration_failed", "Registration failed").Wrap(err))
 		return
 	}
+	metrics.Registered()
 
-	writeJSON(w, map[string]string{"message": "Registrasi berhasil"})
+	writeJSON(w, map[string]string{"message": i18n.T(r.Context(), "message.registration_successful", nil)})
 }
 
 func (h *HttpServer) HandleListPublicProducts(w http.ResponseWriter, r *http.Request) {
-	products, err := h.PublicQ.ListAvailableProducts(r.Context())
+	products, err := h.Products.ListAvailable(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, r, err)
 		return
 	}
-	writeJSON(w, products)
+
+	resp := make([]productSummary, len(products))
+	for i, p := range products {
+		resp[i] = productSummary{
+			ProductID:   p.ProductID,
+			ImageUrl:    p.ImageUrl,
+			ProductName: p.ProductName,
+			Stock:       p.Stock,
+			UnitPrice:   p.UnitPrice,
+			Category:    p.Category,
+		}
+	}
+	writeJSON(w, resp)
 }
 
 func (h *HttpServer) HandleGetProductDetail(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, _ := strconv.Atoi(idStr)
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-	product, err := h.PublicQ.GetProductDetail(r.Context(), int32(id))
+	product, err := h.Products.Get(r.Context(), id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			http.Error(w, "Product not found", 404)
-		} else {
-			http.Error(w, err.Error(), 500)
-		}
+		writeError(w, r, err)
 		return
 	}
 	writeJSON(w, product)
@@ -105,43 +155,24 @@ func (h *HttpServer) HandleGetProductDetail(w http.ResponseWriter, r *http.Reque
 // Mobile
 
 func (h *HttpServer) HandleAdminListProducts(w http.ResponseWriter, r *http.Request) {
-	products, err := h.AdminQ.ListAllProductsAdmin(r.Context())
+	products, err := h.Products.ListAll(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, r, err)
 		return
 	}
 	writeJSON(w, products)
 }
 
 func (h *HttpServer) HandleCreateProduct(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Name        string  `json:"name"`
-		Category    string  `json:"category"`
-		Description string  `json:"description"`
-		Price       float64 `json:"price"`
-		ImageUrl    string  `json:"image_url"`
-		Stock       int32   `json:"stock"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", 400)
+	var req productRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	var priceNum pgtype.Numeric
-	priceNum.Scan(fmt.Sprintf("%f", req.Price))
-
-	id, err := h.AdminQ.CreateProduct(r.Context(), admindb.CreateProductParams{
-		ProductName: req.Name,
-		Category:    req.Category,
-		Description: req.Description,
-		UnitPrice:   priceNum,
-		ImageUrl:    req.ImageUrl,
-		Stock:       req.Stock,
-	})
-
+	id, err := h.Products.Create(r.Context(), req.input())
 	if err != nil {
-		http.Error(w, "Gagal create product: "+err.Error(), 500)
+		writeError(w, r, err)
 		return
 	}
 
@@ -149,38 +180,20 @@ func (h *HttpServer) HandleCreateProduct(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *HttpServer) HandleUpdateProduct(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, _ := strconv.Atoi(idStr)
-
-	var req struct {
-		Name        string  `json:"name"`
-		Category    string  `json:"category"`
-		Description string  `json:"description"`
-		Price       float64 `json:"price"`
-		ImageUrl    string  `json:"image_url"`
-		Stock       int32   `json:"stock"`
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", 400)
+	var req productRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	var priceNum pgtype.Numeric
-	priceNum.Scan(fmt.Sprintf("%f", req.Price))
-
-	err := h.AdminQ.UpdateProduct(r.Context(), admindb.UpdateProductParams{
-		ProductID:   int32(id),
-		ProductName: req.Name,
-		Category:    req.Category,
-		Description: req.Description,
-		UnitPrice:   priceNum,
-		ImageUrl:    req.ImageUrl,
-		Stock:       req.Stock,
-	})
-
-	if err != nil {
-		http.Error(w, "Gagal update: "+err.Error(), 500)
+	if err := h.Products.Update(r.Context(), id, req.input()); err != nil {
+		writeError(w, r, err)
 		return
 	}
 	writeJSON(w, map[string]string{"status": "success"})
@@ -188,119 +201,110 @@ func (h *HttpServer) HandleUpdateProduct(w http.ResponseWriter, r *http.Request)
 
 func (h *HttpServer) HandleCreateOrder(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		UserID      string  `json:"user_id"`
-		ProductID   int32   `json:"product_id"`
-		Quantity    int32   `json:"quantity"`
-		TotalAmount float64 `json:"total_amount"`
-		Status      string  `json:"status"`
+		UserID      string  `json:"user_id" validate:"required,uuid"`
+		ProductID   int32   `json:"product_id" validate:"required,min=1"`
+		Quantity    int32   `json:"quantity" validate:"min=1,max=1000"`
+		TotalAmount float64 `json:"total_amount" validate:"min=0"`
+		Status      string  `json:"status" validate:"omitempty,oneof=pending process done canceled"`
+		AddressID   *int32  `json:"address_id" validate:"min=1"`
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid Input Format: "+err.Error(), 400)
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
-
-	var userUUID pgtype.UUID
-	if err := userUUID.Scan(req.UserID); err != nil {
-		http.Error(w, "Invalid UUID format", 400)
-		return
+	if req.Status == "" {
+		req.Status = "pending"
 	}
 
-	var totalAmountNum pgtype.Numeric
-	if err := totalAmountNum.Scan(fmt.Sprintf("%.2f", req.TotalAmount)); err != nil {
-		http.Error(w, "Invalid Total Amount", 400)
-		return
-	}
-
-	err := h.AdminQ.CreateOrder(r.Context(), admindb.CreateOrderParams{
-		UserID:      userUUID,
+	err := h.Orders.Create(r.Context(), service.NewOrder{
+		UserID:      req.UserID,
 		ProductID:   req.ProductID,
 		Quantity:    req.Quantity,
-		TotalAmount: totalAmountNum,
+		TotalAmount: req.TotalAmount,
 		Status:      req.Status,
+		AddressID:   req.AddressID,
 	})
-
 	if err != nil {
-		fmt.Println("DB ERROR:", err)
-		http.Error(w, "Database Error: "+err.Error(), 500)
+		writeError(w, r, err)
 		return
 	}
+	metrics.OrderCreated()
 
-	writeJSON(w, map[string]string{"message": "Order Created Successfully"})
+	writeJSON(w, map[string]string{"message": i18n.T(r.Context(), "message.order_created", nil)})
 }
 
 func (h *HttpServer) HandleDeleteProduct(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, _ := strconv.Atoi(idStr)
-
-	err := h.AdminQ.DeleteProduct(r.Context(), int32(id))
+	id, err := pathID(r, "id")
 	if err != nil {
-		http.Error(w, "Gagal delete: "+err.Error(), 500)
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.Products.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
 		return
 	}
 	writeJSON(w, map[string]string{"status": "deleted"})
 }
 
 func (h *HttpServer) HandleListOrders(w http.ResponseWriter, r *http.Request) {
-	orders, err := h.AdminQ.ListOrders(r.Context())
+	orders, err := h.Orders.List(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), 500)
+		writeError(w, r, err)
 		return
 	}
-	writeJSON(w, orders)
+
+	// status tetap kode mentah untuk logika client, status_label untuk ditampilkan
+	type orderResponse struct {
+		service.Order
+		StatusLabel string `json:"status_label"`
+	}
+	resp := make([]orderResponse, len(orders))
+	for i, o := range orders {
+		resp[i] = orderResponse{Order: o, StatusLabel: i18n.StatusLabel(r.Context(), o.Status)}
+	}
+	writeJSON(w, resp)
 }
 
 func (h *HttpServer) HandleUpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	orderID, _ := strconv.Atoi(idStr)
+	orderID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
 	var req struct {
-		Status string `json:"status"`
+		Status string `json:"status" validate:"required,oneof=pending process done canceled"`
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", 400)
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	tx, err := h.DB.Begin(r.Context())
+	change, err := h.Orders.UpdateStatus(r.Context(), orderID, req.Status)
 	if err != nil {
-		http.Error(w, "Tx Error", 500)
+		writeError(w, r, err)
 		return
 	}
-	defer tx.Rollback(r.Context())
 
-	qtx := h.AdminQ.WithTx(tx)
-
-	err = qtx.UpdateOrderStatus(r.Context(), admindb.UpdateOrderStatusParams{
-		OrderID: int32(orderID),
-		Status:  req.Status,
-	})
-	if err != nil {
-		http.Error(w, "Gagal update status: "+err.Error(), 500)
-		return
+	if change.From != change.To {
+		metrics.OrderStatusChanged(change.From, change.To)
 	}
-
-	if req.Status == "done" {
-		orderInfo, err := qtx.GetOrderQuantityAndProduct(r.Context(), int32(orderID))
-		if err != nil {
-			http.Error(w, "Order info not found", 404)
-			return
-		}
-
-		err = qtx.DecreaseProductStock(r.Context(), admindb.DecreaseProductStockParams{
-			ProductID: orderInfo.ProductID,
-			Stock:     orderInfo.Quantity,
-		})
-		if err != nil {
-			http.Error(w, "Gagal kurangi stok", 400)
-			return
-		}
+	if change.StockOut {
+		metrics.StockOut()
 	}
 
-	if err := tx.Commit(r.Context()); err != nil {
-		http.Error(w, "Commit Failed", 500)
-		return
+	// Teks notifikasi untuk pembeli, dalam bahasa request
+	params := map[string]string{
+		"order_id": fmt.Sprint(orderID),
+		"status":   i18n.StatusLabel(r.Context(), req.Status),
 	}
-
-	writeJSON(w, map[string]string{"status": "updated"})
+	writeJSON(w, map[string]any{
+		"status": "updated",
+		"notification": map[string]string{
+			"title": i18n.T(r.Context(), "notification.order_status_changed.title", params),
+			"body":  i18n.T(r.Context(), "notification.order_status_changed.body", params),
+		},
+	})
 }
@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"backend/pkg/auth"
+	"backend/pkg/config"
+	"backend/pkg/handler"
+	"backend/pkg/ratelimit"
+)
+
+// newStandaloneEnv sama seperti newEnv, tapi login ditangani Local di atas
+// auth.users database test (AUTH_MODE=standalone), tanpa fake Supabase.
+func newStandaloneEnv(t *testing.T) (*env, *auth.Local) {
+	t.Helper()
+	e := newEnv(t)
+
+	local := auth.NewLocal(auth.NewPostgresStore(testDB), testJWTSecret)
+	cfg := &config.Config{
+		Env:             config.EnvDevelopment,
+		DefaultLanguage: "en",
+		Auth:            config.Auth{Mode: config.AuthStandalone, JWTSecret: testJWTSecret},
+		Health:          config.Health{Timeout: 2 * time.Second},
+	}
+	e.router = handler.NewRouter(handler.RouterDeps{
+		Config:         cfg,
+		DB:             testDB,
+		Auth:           local,
+		RateLimitStore: ratelimit.NewMemoryStore(),
+	})
+	return e, local
+}
+
+func TestStandaloneAuth(t *testing.T) {
+	e, local := newStandaloneEnv(t)
+
+	var sent []string
+	local.SendEmail = func(ctx context.Context, to, kind, token, redirectTo string) error {
+		sent = append(sent, token)
+		return nil
+	}
+
+	body := registerBody("Budi@Example.com")
+	e.send(http.MethodPost, "/api/auth/register", "", body).expect(http.StatusOK, nil)
+
+	// Trigger dari auth.Bootstrap membuat profil dari metadata signup
+	if got := e.queryInt(`SELECT COUNT(*) FROM users u JOIN auth.users au ON au.id = u.user_id WHERE au.email = 'budi@example.com' AND u.username = 'budi'`); got != 1 {
+		t.Fatalf("profile rows = %d, want 1", got)
+	}
+	if got := e.queryInt(`SELECT COUNT(*) FROM auth.users WHERE encrypted_password LIKE '$2%' AND email_confirmed_at IS NOT NULL`); got != 1 {
+		t.Errorf("bcrypt-hashed confirmed accounts = %d, want 1", got)
+	}
+
+	e.send(http.MethodPost, "/api/auth/register", "", registerBody("BUDI@example.com")).
+		expectError(http.StatusBadRequest, "registration_failed")
+	// Username yang sudah dipakai membatalkan seluruh signup
+	e.send(http.MethodPost, "/api/auth/register", "", registerBody("budi2@example.com")).
+		expectError(http.StatusBadRequest, "registration_failed")
+	if got := e.queryInt(`SELECT COUNT(*) FROM auth.users`); got != 1 {
+		t.Errorf("auth.users rows = %d, want 1", got)
+	}
+
+	login := func(password string) *response {
+		return e.send(http.MethodPost, "/api/auth/login", "", map[string]string{"email": "budi@example.com", "password": password})
+	}
+	login("salah").expectError(http.StatusUnauthorized, "invalid_credentials")
+
+	var session struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		User         struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"user"`
+	}
+	login("secret123").expect(http.StatusOK, &session)
+	if session.User.Username != "budi" {
+		t.Fatalf("session user = %+v", session.User)
+	}
+	e.get("/api/auth/me", session.AccessToken).expect(http.StatusOK, nil)
+
+	oldRefresh := session.RefreshToken
+	e.send(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": oldRefresh}).expect(http.StatusOK, &session)
+	e.send(http.MethodPost, "/api/auth/refresh", "", map[string]string{"refresh_token": oldRefresh}).
+		expectError(http.StatusUnauthorized, "invalid_refresh_token")
+
+	e.send(http.MethodPost, "/api/auth/logout", session.AccessToken, nil).expect(http.StatusOK, nil)
+	if got := e.queryInt(`SELECT COUNT(*) FROM auth.tokens WHERE kind = 'refresh'`); got != 0 {
+		t.Errorf("refresh tokens after logout = %d, want 0", got)
+	}
+
+	e.send(http.MethodPost, "/api/auth/password/forgot", "", map[string]string{"email": "budi@example.com"}).expect(http.StatusOK, nil)
+	if len(sent) != 1 {
+		t.Fatalf("sent %d reset emails, want 1", len(sent))
+	}
+	e.send(http.MethodPost, "/api/auth/password/reset", "", map[string]string{"token_hash": sent[0], "password": "rahasia456"}).
+		expect(http.StatusOK, nil)
+	login("secret123").expectError(http.StatusUnauthorized, "invalid_credentials")
+	login("rahasia456").expect(http.StatusOK, nil)
+
+	// Menghapus akun ikut menghapus profil dan token
+	if err := local.DeleteUser(context.Background(), session.User.ID); err != nil {
+		t.Fatalf("delete user: %v", err)
+	}
+	if _, err := local.GetUser(context.Background(), session.User.ID); !errors.Is(err, auth.ErrUserNotFound) {
+		t.Errorf("GetUser after delete: err = %v, want ErrUserNotFound", err)
+	}
+	if got := e.queryInt(`SELECT COUNT(*) FROM users`) + e.queryInt(`SELECT COUNT(*) FROM auth.tokens`); got != 0 {
+		t.Errorf("rows left after delete = %d, want 0", got)
+	}
+}
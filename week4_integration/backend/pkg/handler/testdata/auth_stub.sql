@@ -0,0 +1,12 @@
+-- Pengganti minimal schema auth milik Supabase (GoTrue) untuk integration test.
+-- Hanya kolom yang dibaca backend dan fake Supabase yang ada di sini.
+CREATE SCHEMA IF NOT EXISTS auth;
+
+CREATE TABLE auth.users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email TEXT UNIQUE,
+    encrypted_password TEXT,
+    email_confirmed_at TIMESTAMPTZ,
+    raw_user_meta_data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,161 @@
+// Package i18n memilih bahasa respons dari Accept-Language dan menerjemahkan
+// pesan berdasarkan key, mis. "error.product_not_found" atau "status.done".
+// Katalog ada di locales/<bahasa>.json; semua bahasa wajib punya key yang sama
+// dengan bahasa Inggris (dicek saat init).
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var (
+	English    = language.English
+	Indonesian = language.Indonesian
+
+	supported = []language.Tag{English, Indonesian}
+	catalogs  = map[language.Tag]map[string]string{}
+
+	defaultLang        = English
+	matchTags, matcher = newMatcher(defaultLang)
+)
+
+func init() {
+	for _, tag := range supported {
+		data, err := locales.ReadFile("locales/" + tag.String() + ".json")
+		if err != nil {
+			panic(fmt.Sprintf("i18n: missing catalog for %s: %v", tag, err))
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", tag, err))
+		}
+		catalogs[tag] = catalog
+	}
+
+	for _, tag := range supported {
+		if len(catalogs[tag]) != len(catalogs[English]) {
+			panic(fmt.Sprintf("i18n: catalog %s has %d keys, en has %d", tag, len(catalogs[tag]), len(catalogs[English])))
+		}
+	}
+	for key := range catalogs[English] {
+		for _, tag := range supported {
+			if _, ok := catalogs[tag][key]; !ok {
+				panic(fmt.Sprintf("i18n: catalog %s is missing key %q", tag, key))
+			}
+		}
+	}
+}
+
+// newMatcher menaruh bahasa default di depan, karena itu yang dipilih
+// matcher kalau tidak ada yang cocok.
+func newMatcher(def language.Tag) ([]language.Tag, language.Matcher) {
+	tags := []language.Tag{def}
+	for _, tag := range supported {
+		if tag != def {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, language.NewMatcher(tags)
+}
+
+// SetDefault mengganti bahasa default, mis. dari DEFAULT_LANGUAGE.
+func SetDefault(lang string) error {
+	tag, err := language.Parse(lang)
+	if err != nil || !slices.Contains(supported, tag) {
+		return fmt.Errorf("unsupported language %q", lang)
+	}
+	defaultLang = tag
+	matchTags, matcher = newMatcher(tag)
+	return nil
+}
+
+// Supported mengembalikan kode bahasa yang punya katalog, mis. "en", "id".
+func Supported() []string {
+	codes := make([]string, len(supported))
+	for i, tag := range supported {
+		codes[i] = tag.String()
+	}
+	return codes
+}
+
+// Match memilih bahasa katalog terbaik untuk header Accept-Language.
+func Match(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return defaultLang
+	}
+	_, idx, conf := matcher.Match(tags...)
+	if conf == language.No {
+		return defaultLang
+	}
+	return matchTags[idx]
+}
+
+type ctxKey struct{}
+
+func WithLanguage(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, ctxKey{}, tag)
+}
+
+// FromContext mengembalikan bahasa request, atau bahasa default.
+func FromContext(ctx context.Context) language.Tag {
+	if tag, ok := ctx.Value(ctxKey{}).(language.Tag); ok {
+		return tag
+	}
+	return defaultLang
+}
+
+// Middleware menyimpan bahasa pilihan di context dan mengirim Content-Language.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tag := Match(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", tag.String())
+		next.ServeHTTP(w, r.WithContext(WithLanguage(r.Context(), tag)))
+	})
+}
+
+// Lookup menerjemahkan key ke bahasa di ctx. Placeholder {nama} diganti dari
+// params. ok false kalau key tidak ada di katalog.
+func Lookup(ctx context.Context, key string, params map[string]string) (string, bool) {
+	msg, ok := catalogs[FromContext(ctx)][key]
+	if !ok {
+		return "", false
+	}
+	if len(params) > 0 {
+		pairs := make([]string, 0, len(params)*2)
+		for k, v := range params {
+			pairs = append(pairs, "{"+k+"}", v)
+		}
+		msg = strings.NewReplacer(pairs...).Replace(msg)
+	}
+	return msg, true
+}
+
+// T seperti Lookup, tapi mengembalikan key-nya sendiri kalau tidak ditemukan
+// supaya key yang lupa ditambahkan mudah terlihat.
+func T(ctx context.Context, key string, params map[string]string) string {
+	if msg, ok := Lookup(ctx, key, params); ok {
+		return msg
+	}
+	return key
+}
+
+// StatusLabel adalah nama status order yang ditampilkan ke pengguna.
+func StatusLabel(ctx context.Context, status string) string {
+	if msg, ok := Lookup(ctx, "status."+status, nil); ok {
+		return msg
+	}
+	return status
+}
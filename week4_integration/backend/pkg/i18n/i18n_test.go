@@ -0,0 +1,88 @@
+package i18n_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"backend/pkg/i18n"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   language.Tag
+	}{
+		{"", i18n.English},
+		{"id", i18n.Indonesian},
+		{"id-ID,id;q=0.9,en;q=0.8", i18n.Indonesian},
+		{"en-US,en;q=0.9", i18n.English},
+		{"fr-FR,id;q=0.5", i18n.Indonesian},
+		{"fr-FR", i18n.English},
+		{";;;", i18n.English},
+	}
+	for _, tt := range tests {
+		if got := i18n.Match(tt.header); got != tt.want {
+			t.Errorf("Match(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	t.Cleanup(func() { i18n.SetDefault("en") })
+
+	if err := i18n.SetDefault("fr"); err == nil {
+		t.Error("SetDefault(fr) accepted an unsupported language")
+	}
+	if err := i18n.SetDefault("id"); err != nil {
+		t.Fatal(err)
+	}
+	if got := i18n.Match("fr-FR"); got != i18n.Indonesian {
+		t.Errorf("fallback = %s, want id", got)
+	}
+	if got := i18n.FromContext(context.Background()); got != i18n.Indonesian {
+		t.Errorf("FromContext without language = %s, want id", got)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	id := i18n.WithLanguage(context.Background(), i18n.Indonesian)
+
+	if got := i18n.T(id, "validation.max_length", map[string]string{"param": "5"}); got == "" || got == "validation.max_length" {
+		t.Errorf("T = %q", got)
+	}
+	en, _ := i18n.Lookup(context.Background(), "validation.max_length", map[string]string{"param": "5"})
+	idMsg, _ := i18n.Lookup(id, "validation.max_length", map[string]string{"param": "5"})
+	if en != "must be at most 5 characters" || idMsg != "maksimal 5 karakter" {
+		t.Errorf("en = %q, id = %q", en, idMsg)
+	}
+
+	if _, ok := i18n.Lookup(id, "error.does_not_exist", nil); ok {
+		t.Error("Lookup found a missing key")
+	}
+	if got := i18n.T(id, "error.does_not_exist", nil); got != "error.does_not_exist" {
+		t.Errorf("T missing key = %q, want the key", got)
+	}
+	if got := i18n.StatusLabel(id, "shipped"); got != "shipped" {
+		t.Errorf("StatusLabel unknown = %q", got)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got language.Tag
+	h := i18n.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = i18n.FromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "id-ID")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if got != i18n.Indonesian || w.Header().Get("Content-Language") != "id" || w.Header().Get("Vary") != "Accept-Language" {
+		t.Errorf("language = %s, headers = %v", got, w.Header())
+	}
+}
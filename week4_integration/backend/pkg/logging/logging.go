@@ -0,0 +1,93 @@
+// Package logging menyiapkan log/slog untuk seluruh backend: request ID dan
+// principal ikut tercatat di setiap log yang memakai context request.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"backend/pkg/config"
+)
+
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	principalKey
+)
+
+// New membuat logger sesuai konfigurasi yang menulis ke w.
+func New(c config.Log, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: c.Level}
+
+	var h slog.Handler
+	if c.Format == "text" {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return slog.New(contextHandler{h})
+}
+
+// Setup memasang logger sebagai default, termasuk untuk package log bawaan.
+func Setup(c config.Log) *slog.Logger {
+	logger := New(c, os.Stderr)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// contextHandler menambahkan request_id dan trace_id dari context ke setiap
+// record, supaya log bisa dicocokkan dengan trace-nya.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID mengembalikan request ID dari context, atau "" di luar request.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// principal diisi belakangan oleh middleware auth, setelah access log
+// memasang holder-nya di context.
+type principal struct {
+	userID string
+}
+
+// SetPrincipal mencatat user yang sedang login untuk access log.
+func SetPrincipal(ctx context.Context, userID string) {
+	if p, ok := ctx.Value(principalKey).(*principal); ok {
+		p.userID = userID
+	}
+}
+
+// Err adalah atribut standar untuk error.
+func Err(err error) slog.Attr {
+	return slog.Any("err", err)
+}
@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// QueryName mengambil nama query dari komentar "-- name: X :one" yang
+// ditulis sqlc di awal SQL. Query inline tanpa komentar itu dianggap "raw".
+func QueryName(sql string) string {
+	sql = strings.TrimSpace(sql)
+	if rest, ok := strings.CutPrefix(sql, "-- name: "); ok {
+		if name, _, ok := strings.Cut(rest, " "); ok {
+			return name
+		}
+	}
+	return "raw"
+}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	name  string
+	start time.Time
+}
+
+// QueryTracer mencatat setiap query dengan request ID dari context. Query
+// yang berhasil dicatat di level debug, yang gagal di level warn.
+type QueryTracer struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{name: QueryName(data.SQL), start: time.Now()})
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qs, _ := ctx.Value(queryStartKey{}).(queryStart)
+	attrs := []slog.Attr{
+		slog.String("query", qs.name),
+		slog.Float64("duration_ms", float64(time.Since(qs.start).Microseconds())/1000),
+	}
+
+	if data.Err != nil && data.Err != pgx.ErrNoRows {
+		slog.LogAttrs(ctx, slog.LevelWarn, "db query failed", append(attrs, Err(data.Err))...)
+		return
+	}
+	slog.LogAttrs(ctx, slog.LevelDebug, "db query", append(attrs, slog.Int64("rows", data.CommandTag.RowsAffected()))...)
+}
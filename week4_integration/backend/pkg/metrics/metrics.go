@@ -0,0 +1,88 @@
+// Package metrics mengumpulkan metrik Prometheus untuk HTTP, pool database
+// dan event bisnis. Di Vercel setiap instance punya counter sendiri, jadi
+// angka yang berarti hanya dari server yang berjalan lama (cmd/main.go).
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"backend/pkg/apierror"
+)
+
+const namespace = "astar"
+
+// Registry sendiri (bukan DefaultRegisterer) supaya isi /metrics terkendali.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by chi route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	ordersCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_created_total",
+		Help:      "Orders created.",
+	})
+
+	orderStatusTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_status_transitions_total",
+		Help:      "Order status changes by previous and new status.",
+	}, []string{"from", "to"})
+
+	stockOuts = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "product_stock_outs_total",
+		Help:      "Times a product's stock reached zero after an order was completed.",
+	})
+
+	registrations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Successful user registrations.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		ordersCreated,
+		orderStatusTransitions,
+		stockOuts,
+		registrations,
+	)
+}
+
+func OrderCreated() { ordersCreated.Inc() }
+
+func OrderStatusChanged(from, to string) { orderStatusTransitions.WithLabelValues(from, to).Inc() }
+
+func StockOut() { stockOuts.Inc() }
+
+func Registered() { registrations.Inc() }
+
+// Handler melayani /metrics hanya untuk request dengan bearer token yang benar.
+func Handler(token string) http.Handler {
+	metrics := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	expected := []byte("Bearer " + token)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, expected) != 1 {
+			apierror.Write(w, r, apierror.Unauthorized("Invalid metrics token").WithKey("error.unauthorized.metrics_token", nil))
+			return
+		}
+		metrics.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,326 @@
+// Package migrate menjalankan migrasi schema bernomor (lihat folder migrations)
+// dan mencatat versi yang sudah diterapkan di tabel schema_migrations.
+//
+// Setiap migrasi berjalan dalam transaksi bersama baris schema_migrations-nya,
+// jadi migrasi yang gagal tidak meninggalkan schema setengah jadi. Akibatnya
+// statement yang tidak boleh di dalam transaksi (mis. CREATE INDEX
+// CONCURRENTLY) tidak didukung. Semua perintah memegang advisory lock, jadi
+// dua proses migrate (atau deploy bersamaan) tidak pernah jalan berbarengan.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockKey adalah kunci pg_advisory_lock untuk migrasi ("astarmig" dalam hex).
+const lockKey int64 = 0x61737461726d6967
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status adalah satu migrasi beserta waktu penerapannya (nil kalau belum).
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+func (s Status) Applied() bool { return s.AppliedAt != nil }
+
+// Load membaca pasangan NNNN_nama.up.sql/NNNN_nama.down.sql dari fsys,
+// diurutkan berdasarkan versi.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() || !fileName.MatchString(e.Name()) {
+			continue
+		}
+		parts := fileName.FindStringSubmatch(e.Name())
+		version, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("%s: invalid version", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = m
+		}
+		if m.Name != parts[2] {
+			return nil, fmt.Errorf("version %d is used by both %q and %q", version, m.Name, parts[2])
+		}
+		if parts[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both .up.sql and .down.sql", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return migrations, nil
+}
+
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+}
+
+// New memuat migrasi dari fsys, biasanya migrations.FS yang di-embed.
+func New(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	return &Migrator{pool: pool, migrations: migrations}, nil
+}
+
+// Latest adalah versi tertinggi yang dikenal binary ini (0 kalau tidak ada).
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status mengembalikan semua migrasi yang dikenal beserta status penerapannya.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var out []Status
+	err := m.withLock(ctx, func(conn *pgx.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			s := Status{Migration: mig}
+			if at, ok := applied[mig.Version]; ok {
+				s.AppliedAt = &at
+			}
+			out = append(out, s)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// Up menerapkan semua migrasi yang belum diterapkan.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down membatalkan satu migrasi terakhir yang sudah diterapkan.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgx.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range slices.Backward(m.migrations) {
+			if _, ok := applied[mig.Version]; ok {
+				return m.apply(ctx, conn, mig, false)
+			}
+		}
+		slog.InfoContext(ctx, "no migration to roll back")
+		return nil
+	})
+}
+
+// To menerapkan atau membatalkan migrasi sampai versi target tepat menjadi
+// versi terakhir yang diterapkan. Target 0 membatalkan semuanya.
+func (m *Migrator) To(ctx context.Context, target int64) error {
+	if target != 0 && !m.known(target) {
+		return fmt.Errorf("unknown migration version %d", target)
+	}
+
+	return m.withLock(ctx, func(conn *pgx.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		// Database lebih baru dari binary: jangan menebak cara menurunkannya
+		for v := range applied {
+			if !m.known(v) {
+				return fmt.Errorf("database has migration %d which this binary does not know; deploy a newer build", v)
+			}
+		}
+
+		changed := false
+		for _, mig := range slices.Backward(m.migrations) {
+			if _, ok := applied[mig.Version]; ok && mig.Version > target {
+				if err := m.apply(ctx, conn, mig, false); err != nil {
+					return err
+				}
+				changed = true
+			}
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= target {
+				if err := m.apply(ctx, conn, mig, true); err != nil {
+					return err
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			slog.InfoContext(ctx, "schema is up to date", "version", target)
+		}
+		return nil
+	})
+}
+
+// Force menandai versi sampai target sebagai sudah diterapkan tanpa
+// menjalankan SQL-nya. Dipakai sekali untuk database yang dibuat dari
+// schema.sql lama sebelum ada migrasi.
+func (m *Migrator) Force(ctx context.Context, target int64) error {
+	if target != 0 && !m.known(target) {
+		return fmt.Errorf("unknown migration version %d", target)
+	}
+
+	return m.withLock(ctx, func(conn *pgx.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+				return err
+			}
+			for _, mig := range m.migrations {
+				if mig.Version > target {
+					break
+				}
+				if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name); err != nil {
+					return err
+				}
+			}
+			slog.WarnContext(ctx, "schema version forced", "version", target)
+			return nil
+		})
+	})
+}
+
+func (m *Migrator) known(version int64) bool {
+	return slices.ContainsFunc(m.migrations, func(mig Migration) bool { return mig.Version == version })
+}
+
+// apply menjalankan up/down satu migrasi dalam satu transaksi.
+func (m *Migrator) apply(ctx context.Context, conn *pgx.Conn, mig Migration, up bool) error {
+	direction, sql := "up", mig.Up
+	if !up {
+		direction, sql = "down", mig.Down
+	}
+
+	start := time.Now()
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return err
+		}
+		var err error
+		if up {
+			_, err = tx.Exec(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", mig.Version, mig.Name)
+		} else {
+			_, err = tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("migration %d_%s %s: %w", mig.Version, mig.Name, direction, err)
+	}
+
+	slog.InfoContext(ctx, "migration applied", "version", mig.Version, "name", mig.Name,
+		"direction", direction, "duration_ms", time.Since(start).Milliseconds())
+	return nil
+}
+
+// withLock memegang advisory lock di satu koneksi selama fn berjalan. Lock
+// session-level dilepas otomatis kalau koneksi putus.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	c, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Release()
+	conn := c.Conn()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", lockKey).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		slog.InfoContext(ctx, "another migration is running, waiting for lock")
+		if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+			return err
+		}
+	}
+	defer func() {
+		// Context bisa sudah dibatalkan, tapi lock tetap harus dilepas
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			slog.Warn("release migration lock", "err", err)
+		}
+	}()
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+func ensureTable(ctx context.Context, conn *pgx.Conn) error {
+	_, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		ALTER TABLE schema_migrations ENABLE ROW LEVEL SECURITY;
+	`)
+	return err
+}
+
+func appliedVersions(ctx context.Context, conn *pgx.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	applied := map[int64]time.Time{}
+	var version int64
+	var at time.Time
+	_, err = pgx.ForEachRow(rows, []any{&version, &at}, func() error {
+		applied[version] = at
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read schema_migrations: %w", err)
+	}
+	return applied, nil
+}
@@ -0,0 +1,87 @@
+package migrate_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"backend/migrations"
+	"backend/pkg/migrate"
+)
+
+func file(body string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(body)}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0010_orders.up.sql":      file("CREATE TABLE orders ();"),
+		"0010_orders.down.sql":    file("DROP TABLE orders;"),
+		"0002_users.up.sql":       file("CREATE TABLE users ();"),
+		"0002_users.down.sql":     file("DROP TABLE users;"),
+		"README.md":               file("bukan migrasi"),
+		"0003_Bad-Name.up.sql":    file("diabaikan karena tidak cocok pola nama"),
+		"archive/0001_x.up.sql":   file("subfolder tidak dibaca"),
+		"archive/0001_x.down.sql": file("subfolder tidak dibaca"),
+	}
+	got, err := migrate.Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Version != 2 || got[1].Version != 10 {
+		t.Fatalf("migrations = %+v, want 2 then 10", got)
+	}
+	if got[0].Name != "users" || got[0].Up != "CREATE TABLE users ();" || got[0].Down != "DROP TABLE users;" {
+		t.Errorf("first = %+v", got[0])
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{"duplicate version", fstest.MapFS{
+			"0001_init.up.sql":    file("up"),
+			"0001_init.down.sql":  file("down"),
+			"0001_other.up.sql":   file("up"),
+			"0001_other.down.sql": file("down"),
+		}, "version 1 is used by both"},
+		{"missing down", fstest.MapFS{
+			"0001_init.up.sql": file("up"),
+		}, "migration 1_init needs both"},
+		{"missing up", fstest.MapFS{
+			"0001_init.down.sql": file("down"),
+		}, "needs both .up.sql and .down.sql"},
+		{"empty down", fstest.MapFS{
+			"0001_init.up.sql":   file("up"),
+			"0001_init.down.sql": file(""),
+		}, "needs both"},
+		{"version zero", fstest.MapFS{
+			"0000_init.up.sql":   file("up"),
+			"0000_init.down.sql": file("down"),
+		}, "invalid version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := migrate.Load(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Migrasi yang di-embed ke binary harus selalu bisa dimuat dan bernomor urut
+func TestLoadEmbedded(t *testing.T) {
+	got, err := migrate.Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d_%s at position %d, want version %d", m.Version, m.Name, i, i+1)
+		}
+	}
+}
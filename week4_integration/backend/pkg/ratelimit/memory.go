@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryStore menyimpan bucket di memori proses.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(p.Burst), updatedAt: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = min(float64(p.Burst), b.tokens+now.Sub(b.updatedAt).Seconds()*p.rate())
+	b.updatedAt = now
+
+	s.takes++
+	if s.takes%1000 == 0 {
+		s.evict(now)
+	}
+
+	if b.tokens < 1 {
+		return Result{Allowed: false, Remaining: 0, RetryAfter: p.retryAfter(b.tokens)}, nil
+	}
+
+	b.tokens--
+	return Result{Allowed: true, Remaining: int(b.tokens)}, nil
+}
+
+// evict membuang bucket yang sudah lama tidak dipakai supaya map tidak tumbuh terus.
+func (s *MemoryStore) evict(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.updatedAt) > 24*time.Hour {
+			delete(s.buckets, key)
+		}
+	}
+}
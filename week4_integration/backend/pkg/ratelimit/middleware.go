@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"backend/pkg/apierror"
+)
+
+// KeyFunc menentukan identitas pemilik bucket untuk satu request.
+type KeyFunc func(r *http.Request) string
+
+// ByIP memakai alamat IP client. Di belakang proxy, pasang middleware.RealIP
+// lebih dulu supaya RemoteAddr berisi IP asli.
+func ByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return "ip:" + r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// ByUserOrIP memakai user ID dari AuthRequired/AdminOnly kalau ada, selain itu IP.
+func ByUserOrIP(r *http.Request) string {
+	if userID, ok := r.Context().Value("userID").(string); ok && userID != "" {
+		return "user:" + userID
+	}
+	return ByIP(r)
+}
+
+// Middleware menolak request dengan 429 dan header Retry-After kalau bucket habis.
+// Kalau store error, request tetap diteruskan supaya API tidak ikut mati.
+func Middleware(store Store, p Policy, key KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := store.Take(r.Context(), p.Name+":"+key(r), p)
+			if err != nil {
+				slog.WarnContext(r.Context(), "rate limit store error", "policy", p.Name, "err", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(p.Burst))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+
+			if !res.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds()))))
+				apierror.Write(w, r, apierror.New(http.StatusTooManyRequests, apierror.CodeRateLimited, "Too many requests, please retry later"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
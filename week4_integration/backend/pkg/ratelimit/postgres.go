@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Refill dan pengurangan token dihitung dalam satu statement supaya atomik
+// walaupun banyak instance serverless mengakses key yang sama.
+const takeToken = `
+INSERT INTO rate_limits AS rl (bucket_key, tokens, allowed, updated_at)
+VALUES ($1, $2::float8 - 1, TRUE, clock_timestamp())
+ON CONFLICT (bucket_key) DO UPDATE SET
+    allowed = LEAST($2::float8, rl.tokens + EXTRACT(EPOCH FROM clock_timestamp() - rl.updated_at)::float8 * $3::float8) >= 1,
+    tokens = CASE
+        WHEN LEAST($2::float8, rl.tokens + EXTRACT(EPOCH FROM clock_timestamp() - rl.updated_at)::float8 * $3::float8) >= 1
+        THEN LEAST($2::float8, rl.tokens + EXTRACT(EPOCH FROM clock_timestamp() - rl.updated_at)::float8 * $3::float8) - 1
+        ELSE LEAST($2::float8, rl.tokens + EXTRACT(EPOCH FROM clock_timestamp() - rl.updated_at)::float8 * $3::float8)
+    END,
+    updated_at = clock_timestamp()
+RETURNING tokens, allowed`
+
+const evictBuckets = `DELETE FROM rate_limits WHERE updated_at < NOW() - INTERVAL '1 day'`
+
+// PostgresStore menyimpan bucket di tabel rate_limits.
+type PostgresStore struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresStore(db *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	var tokens float64
+	var allowed bool
+	err := s.db.QueryRow(ctx, takeToken, key, float64(p.Burst), p.rate()).Scan(&tokens, &allowed)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Bersih-bersih bucket lama sesekali, tanpa perlu cron terpisah
+	if rand.IntN(1000) == 0 {
+		s.db.Exec(ctx, evictBuckets)
+	}
+
+	if !allowed {
+		return Result{Allowed: false, Remaining: 0, RetryAfter: p.retryAfter(tokens)}, nil
+	}
+	return Result{Allowed: true, Remaining: int(tokens)}, nil
+}
@@ -0,0 +1,62 @@
+// Package ratelimit berisi token bucket rate limiter untuk router chi.
+//
+// Bucket disimpan di Store. MemoryStore cukup untuk server lokal (satu proses),
+// sedangkan deployment serverless (api/index.go) butuh store bersama seperti
+// PostgresStore karena tiap instance tidak berbagi memori. Store lain (mis. Redis)
+// cukup mengimplementasikan interface Store.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Policy mendefinisikan satu bucket: maksimal Burst request sekaligus,
+// diisi ulang Limit token setiap Period.
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+	Burst  int
+}
+
+// rate mengembalikan jumlah token yang bertambah per detik.
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// retryAfter menghitung waktu tunggu sampai bucket punya 1 token lagi.
+func (p Policy) retryAfter(tokens float64) time.Duration {
+	missing := 1 - tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(missing/p.rate()*1000)) * time.Millisecond
+}
+
+// Result adalah hasil satu kali Take.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// Store menyimpan state bucket. Take harus atomik per key.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// Kebijakan per route group.
+var (
+	RegisterPolicy  = Policy{Name: "register", Limit: 5, Period: time.Hour, Burst: 5}
+	LoginPolicy     = Policy{Name: "login", Limit: 10, Period: time.Minute, Burst: 10}
+	AuthEmailPolicy = Policy{Name: "auth-email", Limit: 5, Period: time.Hour, Burst: 3}
+	PublicPolicy    = Policy{Name: "public", Limit: 120, Period: time.Minute, Burst: 60}
+	UserPolicy      = Policy{Name: "user", Limit: 300, Period: time.Minute, Burst: 100}
+
+	// AuthIPPolicy membatasi route yang butuh login per IP sebelum token
+	// dicek. Bucket-nya terpisah dari PublicPolicy supaya browsing katalog
+	// tidak menghabiskan jatah /api/me dan sebaliknya.
+	AuthIPPolicy = Policy{Name: "auth-ip", Limit: 300, Period: time.Minute, Burst: 100}
+)
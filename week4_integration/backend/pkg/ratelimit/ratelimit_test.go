@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// 60 token per menit = 1 token per detik, jadi refill gampang dihitung
+var testPolicy = Policy{Name: "test", Limit: 60, Period: time.Minute, Burst: 2}
+
+func newTestStore() (*MemoryStore, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+	s, now := newTestStore()
+	ctx := context.Background()
+	take := func() Result {
+		t.Helper()
+		res, err := s.Take(ctx, "k", testPolicy)
+		if err != nil {
+			t.Fatalf("take: %v", err)
+		}
+		return res
+	}
+
+	if res := take(); !res.Allowed || res.Remaining != 1 {
+		t.Fatalf("first take = %+v", res)
+	}
+	take()
+	res := take()
+	if res.Allowed || res.RetryAfter != time.Second {
+		t.Fatalf("empty bucket = %+v, want denied with 1s retry", res)
+	}
+
+	// Setengah detik baru mengisi setengah token
+	*now = now.Add(500 * time.Millisecond)
+	if res := take(); res.Allowed || res.RetryAfter != 500*time.Millisecond {
+		t.Fatalf("half refilled = %+v", res)
+	}
+	*now = now.Add(500 * time.Millisecond)
+	if res := take(); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("refilled = %+v", res)
+	}
+
+	// Refill tidak pernah melebihi Burst
+	*now = now.Add(time.Hour)
+	if res := take(); res.Remaining != testPolicy.Burst-1 {
+		t.Errorf("after idle = %+v, want capped at burst", res)
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	s, _ := newTestStore()
+	ctx := context.Background()
+	for range testPolicy.Burst {
+		s.Take(ctx, "a", testPolicy)
+	}
+	if res, _ := s.Take(ctx, "b", testPolicy); !res.Allowed {
+		t.Errorf("key b limited by key a: %+v", res)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	s, _ := newTestStore()
+	h := Middleware(s, testPolicy, ByIP)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	call := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	call("192.0.2.1:1000")
+	// Port berbeda tetap IP yang sama
+	call("192.0.2.1:2000")
+	w := call("192.0.2.1:3000")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Fatalf("status = %d, Retry-After = %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := call("192.0.2.2:1000"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != "1" {
+		t.Errorf("other IP: status = %d, remaining = %q", w.Code, w.Header().Get("X-RateLimit-Remaining"))
+	}
+	if _, ok := s.buckets["test:ip:192.0.2.1"]; !ok {
+		t.Errorf("bucket keys = %v, want policy:ip:host", s.buckets)
+	}
+}
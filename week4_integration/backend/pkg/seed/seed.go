@@ -0,0 +1,308 @@
+// Package seed memuat fixture YAML/JSON (produk, user, order) ke database.
+// Seed bersifat idempoten: menjalankan fixture yang sama dua kali tidak
+// menggandakan data, hanya memperbarui baris yang sudah ada.
+//
+// Kunci idempotensi per jenis data:
+//   - product: product_name
+//   - user: email (auth.users), profil di-upsert berdasarkan user_id
+//   - order: user + product + order_date
+//
+// Kategori bukan tabel sendiri (kolom products.category). Daftar categories di
+// fixture dipakai untuk mengecek salah ketik kategori produk.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"gopkg.in/yaml.v3"
+
+	"backend/pkg/validate"
+)
+
+//go:embed fixtures/*.yaml
+var fixtures embed.FS
+
+type Product struct {
+	Name        string  `json:"name" yaml:"name" validate:"required,max=100"`
+	Category    string  `json:"category" yaml:"category" validate:"required,max=50"`
+	Description string  `json:"description" yaml:"description" validate:"max=5000"`
+	Price       float64 `json:"price" yaml:"price" validate:"gt=0,max=1000000000"`
+	ImageURL    string  `json:"image_url" yaml:"image_url" validate:"max=2048"`
+	Stock       int32   `json:"stock" yaml:"stock" validate:"min=0,max=1000000"`
+}
+
+// User dibuat di auth.users lewat Options.CreateAuthUser kalau email belum ada.
+type User struct {
+	Email       string `json:"email" yaml:"email" validate:"required,email,max=255"`
+	Password    string `json:"password" yaml:"password" validate:"required,min=6,max=72"`
+	Username    string `json:"username" yaml:"username" validate:"required,max=50"`
+	FullName    string `json:"full_name" yaml:"full_name" validate:"required,max=100"`
+	PhoneNumber string `json:"phone_number" yaml:"phone_number" validate:"required,max=20"`
+	Street      string `json:"street" yaml:"street" validate:"required,max=100"`
+	City        string `json:"city" yaml:"city" validate:"required,max=50"`
+	PostCode    string `json:"post_code" yaml:"post_code" validate:"required,max=10"`
+	Role        string `json:"role" yaml:"role" validate:"omitempty,oneof=user admin"`
+}
+
+// Order merujuk user lewat email dan produk lewat nama. Stok produk tidak
+// dikurangi; isi stock di fixture produk sesuai keadaan akhir yang diinginkan.
+type Order struct {
+	User      string    `json:"user" yaml:"user" validate:"required,email"`
+	Product   string    `json:"product" yaml:"product" validate:"required"`
+	Quantity  int32     `json:"quantity" yaml:"quantity" validate:"min=1,max=1000"`
+	Status    string    `json:"status" yaml:"status" validate:"omitempty,oneof=pending process done canceled"`
+	OrderDate time.Time `json:"order_date" yaml:"order_date" validate:"required"`
+}
+
+type Fixture struct {
+	Categories []string  `json:"categories" yaml:"categories"`
+	Products   []Product `json:"products" yaml:"products"`
+	Users      []User    `json:"users" yaml:"users"`
+	Orders     []Order   `json:"orders" yaml:"orders"`
+}
+
+// Demo mengembalikan fixture yang sama dengan data dummy komponen
+// collections di web, jadi tampilan lokal sama dengan fallback-nya.
+func Demo() (*Fixture, error) {
+	data, err := fixtures.ReadFile("fixtures/demo.yaml")
+	if err != nil {
+		return nil, err
+	}
+	return Parse("demo.yaml", bytes.NewReader(data))
+}
+
+// Parse membaca fixture YAML atau JSON (JSON adalah subset YAML) lalu
+// memvalidasinya. Field yang tidak dikenal ditolak supaya salah ketik ketahuan.
+func Parse(name string, r io.Reader) (*Fixture, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+
+	var f Fixture
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if err := f.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &f, nil
+}
+
+func (f *Fixture) validate() error {
+	var problems []string
+	check := func(kind string, i int, v any) {
+		for _, fe := range validate.Struct(v) {
+			problems = append(problems, fmt.Sprintf("%s[%d].%s: %s", kind, i, fe.Field, fe.Message))
+		}
+	}
+
+	for i, p := range f.Products {
+		check("products", i, p)
+		if len(f.Categories) > 0 && !slices.Contains(f.Categories, p.Category) {
+			problems = append(problems, fmt.Sprintf("products[%d].category: %q is not in categories", i, p.Category))
+		}
+	}
+	for i, u := range f.Users {
+		check("users", i, u)
+	}
+	for i, o := range f.Orders {
+		check("orders", i, o)
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid fixture:\n  - " + strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// Merge menggabungkan beberapa fixture sesuai urutan file di command line.
+func Merge(fs ...*Fixture) *Fixture {
+	out := &Fixture{}
+	for _, f := range fs {
+		out.Categories = append(out.Categories, f.Categories...)
+		out.Products = append(out.Products, f.Products...)
+		out.Users = append(out.Users, f.Users...)
+		out.Orders = append(out.Orders, f.Orders...)
+	}
+	return out
+}
+
+type Options struct {
+	// CreateAuthUser membuat akun login dan mengembalikan ID-nya. Hanya
+	// dipanggil untuk email yang belum ada di auth.users; kalau nil, user baru
+	// tidak bisa di-seed.
+	CreateAuthUser func(ctx context.Context, email, password string) (string, error)
+}
+
+// Result menghitung baris yang dibuat dan diperbarui per jenis data.
+type Result struct {
+	ProductsCreated, ProductsUpdated int
+	UsersCreated, UsersUpdated       int
+	OrdersCreated, OrdersSkipped     int
+}
+
+// Apply menjalankan fixture dalam satu transaksi. Akun auth yang baru dibuat
+// lewat CreateAuthUser tidak ikut di-rollback kalau transaksi gagal, tapi
+// run berikutnya akan memakainya kembali karena dicari berdasarkan email.
+func Apply(ctx context.Context, db *pgxpool.Pool, f *Fixture, opts Options) (Result, error) {
+	var res Result
+	err := pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		for _, p := range f.Products {
+			created, err := upsertProduct(ctx, tx, p)
+			if err != nil {
+				return fmt.Errorf("product %q: %w", p.Name, err)
+			}
+			count(created, &res.ProductsCreated, &res.ProductsUpdated)
+		}
+
+		for _, u := range f.Users {
+			created, err := upsertUser(ctx, tx, u, opts)
+			if err != nil {
+				return fmt.Errorf("user %q: %w", u.Email, err)
+			}
+			count(created, &res.UsersCreated, &res.UsersUpdated)
+		}
+
+		for _, o := range f.Orders {
+			created, err := insertOrder(ctx, tx, o)
+			if err != nil {
+				return fmt.Errorf("order %s/%s: %w", o.User, o.Product, err)
+			}
+			count(created, &res.OrdersCreated, &res.OrdersSkipped)
+		}
+		return nil
+	})
+	return res, err
+}
+
+func count(created bool, c, other *int) {
+	if created {
+		*c++
+	} else {
+		*other++
+	}
+}
+
+func upsertProduct(ctx context.Context, tx pgx.Tx, p Product) (bool, error) {
+	tag, err := tx.Exec(ctx, `
+		UPDATE products
+		SET category = $2, description = $3, unit_price = $4, image_url = $5, stock = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE product_name = $1`,
+		p.Name, p.Category, p.Description, p.Price, p.ImageURL, p.Stock)
+	if err != nil || tag.RowsAffected() > 0 {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO products (product_name, category, description, unit_price, image_url, stock)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		p.Name, p.Category, p.Description, p.Price, p.ImageURL, p.Stock)
+	return err == nil, err
+}
+
+func upsertUser(ctx context.Context, tx pgx.Tx, u User, opts Options) (bool, error) {
+	var userID string
+	err := tx.QueryRow(ctx, "SELECT id::text FROM auth.users WHERE lower(email) = lower($1)", u.Email).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if opts.CreateAuthUser == nil {
+			return false, errors.New("not in auth.users and no auth provider configured")
+		}
+		userID, err = opts.CreateAuthUser(ctx, u.Email, u.Password)
+		if err != nil {
+			return false, fmt.Errorf("create auth user: %w", err)
+		}
+		slog.InfoContext(ctx, "auth user created", "email", u.Email, "user_id", userID)
+	} else if err != nil {
+		return false, err
+	}
+
+	role := u.Role
+	if role == "" {
+		role = "user"
+	}
+
+	// xmax = 0 hanya untuk baris yang baru di-insert
+	var created bool
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (user_id, username, full_name, phone_number, role, street, city, post_code)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE
+		SET username = EXCLUDED.username, full_name = EXCLUDED.full_name, phone_number = EXCLUDED.phone_number,
+		    role = EXCLUDED.role, street = EXCLUDED.street, city = EXCLUDED.city, post_code = EXCLUDED.post_code,
+		    updated_at = CURRENT_TIMESTAMP
+		RETURNING xmax = 0`,
+		userID, u.Username, u.FullName, u.PhoneNumber, role, u.Street, u.City, u.PostCode).Scan(&created)
+	return created, err
+}
+
+// insertOrder melewati order yang sudah ada. Total dihitung dari harga produk
+// dan alamat pengiriman diambil dari alamat default user (atau alamat profil
+// kalau belum ada), sama seperti HandleCreateOrder.
+func insertOrder(ctx context.Context, tx pgx.Tx, o Order) (bool, error) {
+	status := o.Status
+	if status == "" {
+		status = "pending"
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO orders (
+		    user_id, product_id, quantity, total_amount, status, order_date,
+		    address_id, ship_recipient_name, ship_phone_number, ship_street, ship_city, ship_post_code
+		)
+		SELECT u.id, p.product_id, $3::int, p.unit_price * $3::int, $4::varchar, $5::timestamp,
+		       a.address_id,
+		       COALESCE(a.recipient_name, pr.full_name), COALESCE(a.phone_number, pr.phone_number),
+		       COALESCE(a.street, pr.street), COALESCE(a.city, pr.city), COALESCE(a.post_code, pr.post_code)
+		FROM auth.users u
+		JOIN products p ON p.product_name = $2
+		LEFT JOIN addresses a ON a.user_id = u.id AND a.is_default
+		LEFT JOIN users pr ON pr.user_id = u.id
+		WHERE lower(u.email) = lower($1)
+		  AND NOT EXISTS (
+		      SELECT 1 FROM orders o
+		      WHERE o.user_id = u.id AND o.product_id = p.product_id AND o.order_date = $5::timestamp
+		  )`,
+		o.User, o.Product, o.Quantity, status, o.OrderDate.UTC())
+	if err != nil || tag.RowsAffected() > 0 {
+		return err == nil, err
+	}
+
+	// Tidak ada baris: order sudah ada, atau user/produk tidak ditemukan
+	var exists bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM auth.users WHERE lower(email) = lower($1))
+		   AND EXISTS (SELECT 1 FROM products WHERE product_name = $2)`,
+		o.User, o.Product).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, errors.New("user or product not found; seed them first")
+	}
+	return false, nil
+}
+
+// PromoteAdmin memberi role admin ke user terdaftar berdasarkan email.
+func PromoteAdmin(ctx context.Context, db *pgxpool.Pool, email string) error {
+	tag, err := db.Exec(ctx, `
+		UPDATE users u SET role = 'admin', updated_at = CURRENT_TIMESTAMP
+		FROM auth.users au
+		WHERE au.id = u.user_id AND lower(au.email) = lower($1)`, email)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("no registered user with email %q", email)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"backend/pkg/app/admindb"
+	"backend/pkg/database"
+)
+
+// auditDiff mengembalikan before/after yang hanya berisi field yang berubah.
+// before nil berarti create, after nil berarti delete; keduanya disimpan utuh.
+// Nilai dibandingkan setelah di-encode ke JSON, jadi yang tercatat sama dengan
+// yang dilihat client API.
+func auditDiff(before, after any) (json.RawMessage, json.RawMessage, error) {
+	b, err := auditJSON(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := auditJSON(after)
+	if err != nil {
+		return nil, nil, err
+	}
+	if b == nil || a == nil {
+		return b, a, nil
+	}
+
+	var bm, am map[string]any
+	if err := json.Unmarshal(b, &bm); err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(a, &am); err != nil {
+		return nil, nil, err
+	}
+	for k, v := range bm {
+		if av, ok := am[k]; ok && reflect.DeepEqual(v, av) {
+			delete(bm, k)
+			delete(am, k)
+		}
+	}
+	if b, err = json.Marshal(bm); err != nil {
+		return nil, nil, err
+	}
+	if a, err = json.Marshal(am); err != nil {
+		return nil, nil, err
+	}
+	return b, a, nil
+}
+
+func auditJSON(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// recordAudit menulis audit log lewat q, yang harus memakai transaksi yang sama
+// dengan perubahannya: kalau perubahan batal, catatannya ikut batal.
+func recordAudit(ctx context.Context, q *admindb.Queries, action, entityType, entityID string, before, after any) error {
+	b, a, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+	actor := actorFrom(ctx)
+	var actorID pgtype.UUID
+	if actor.UserID != "" {
+		if actorID, err = parseUUID(actor.UserID); err != nil {
+			return err
+		}
+	}
+	return q.InsertAuditLog(ctx, admindb.InsertAuditLogParams{
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     b,
+		After:      a,
+		RequestID:  actor.RequestID,
+		Ip:         actor.IP,
+	})
+}
+
+type postgresAudit struct {
+	db    *pgxpool.Pool
+	admin *admindb.Queries
+}
+
+func (s *postgresAudit) List(ctx context.Context, q AuditQuery) ([]AuditEntry, int64, error) {
+	var actorID pgtype.UUID
+	if q.ActorID != "" {
+		id, err := parseUUID(q.ActorID)
+		if err != nil {
+			return nil, 0, err
+		}
+		actorID = id
+	}
+	since := pgtype.Timestamptz{Time: q.Since, Valid: !q.Since.IsZero()}
+	until := pgtype.Timestamptz{Time: q.Until, Valid: !q.Until.IsZero()}
+
+	var rows []admindb.AuditLog
+	var total int64
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		qtx := s.admin.WithTx(tx)
+		rows, err = qtx.ListAuditLog(ctx, admindb.ListAuditLogParams{
+			ActorID:    actorID,
+			Action:     q.Action,
+			EntityType: q.EntityType,
+			EntityID:   q.EntityID,
+			Since:      since,
+			Until:      until,
+			PageSize:   q.PageSize,
+			PageOffset: (q.Page - 1) * q.PageSize,
+		})
+		if err != nil {
+			return err
+		}
+		total, err = qtx.CountAuditLog(ctx, admindb.CountAuditLogParams{
+			ActorID:    actorID,
+			Action:     q.Action,
+			EntityType: q.EntityType,
+			EntityID:   q.EntityID,
+			Since:      since,
+			Until:      until,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := make([]AuditEntry, len(rows))
+	for i, r := range rows {
+		entries[i] = AuditEntry{
+			AuditID:    r.AuditID,
+			ActorID:    r.ActorID,
+			Action:     r.Action,
+			EntityType: r.EntityType,
+			EntityID:   r.EntityID,
+			Before:     r.Before,
+			After:      r.After,
+			RequestID:  r.RequestID,
+			IP:         r.Ip,
+			CreatedAt:  r.CreatedAt,
+		}
+	}
+	return entries, total, nil
+}
@@ -0,0 +1,788 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// MemoryUser adalah akun login (auth.users) beserta profilnya (users).
+// Registered false berarti akun belum punya baris users.
+type MemoryUser struct {
+	ID            string
+	Email         string
+	EmailVerified bool
+	Registered    bool
+	Disabled      bool
+	Profile       Profile
+}
+
+type memoryUser struct {
+	MemoryUser
+	createdAt time.Time
+	seq       int
+}
+
+type memoryOrder struct {
+	id        int32
+	userID    string
+	productID int32
+	quantity  int32
+	total     float64
+	status    string
+	date      time.Time
+	ship      *Address
+}
+
+// Memory menyimpan semua data di memori proses. Satu Memory dipakai bersama
+// oleh ketiga service, jadi order melihat user dan produk yang sama.
+type Memory struct {
+	mu sync.Mutex
+
+	users     map[string]*memoryUser
+	products  map[int32]*Product
+	prices    map[int32]float64
+	orders    map[int32]*memoryOrder
+	addresses map[int32]*Address
+	audit     []AuditEntry
+
+	lastAudit                           int64
+	lastUser                            int
+	lastProduct, lastOrder, lastAddress int32
+	now                                 func() time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		users:     make(map[string]*memoryUser),
+		products:  make(map[int32]*Product),
+		prices:    make(map[int32]float64),
+		orders:    make(map[int32]*memoryOrder),
+		addresses: make(map[int32]*Address),
+		now:       time.Now,
+	}
+}
+
+// Services mengembalikan ketiga service di atas data yang sama.
+func (m *Memory) Services() Services {
+	return Services{
+		Products: memoryProducts{m},
+		Orders:   memoryOrders{m},
+		Users:    memoryUsers{m},
+		Audit:    memoryAudit{m},
+	}
+}
+
+// AddUser menambah akun dan mengembalikan ID-nya (UUID acak kalau kosong).
+func (m *Memory) AddUser(u MemoryUser) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if u.ID == "" {
+		b := make([]byte, 16)
+		rand.Read(b)
+		u.ID = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	}
+	if u.Profile.Role == "" {
+		u.Profile.Role = "user"
+	}
+	u.Profile.UserID = u.ID
+	m.lastUser++
+	m.users[u.ID] = &memoryUser{MemoryUser: u, createdAt: m.now(), seq: m.lastUser}
+	return u.ID
+}
+
+// AddProduct menambah produk tanpa validasi, untuk menyiapkan data test.
+func (m *Memory) AddProduct(in ProductInput) int32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.insertProduct(in)
+}
+
+// AddOrder menambah order langsung tanpa aturan OrderService.Create.
+func (m *Memory) AddOrder(userID string, productID, quantity int32, status string) int32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastOrder++
+	m.orders[m.lastOrder] = &memoryOrder{
+		id:        m.lastOrder,
+		userID:    userID,
+		productID: productID,
+		quantity:  quantity,
+		total:     m.prices[productID] * float64(quantity),
+		status:    status,
+		date:      m.now(),
+	}
+	return m.lastOrder
+}
+
+func (m *Memory) insertProduct(in ProductInput) int32 {
+	m.lastProduct++
+	p := &Product{ProductID: m.lastProduct}
+	m.setProduct(p, in)
+	m.products[p.ProductID] = p
+	return p.ProductID
+}
+
+func (m *Memory) setProduct(p *Product, in ProductInput) {
+	p.ProductName = in.Name
+	p.Category = in.Category
+	p.Description = in.Description
+	p.UnitPrice, _ = numeric(in.Price)
+	p.ImageUrl = in.ImageUrl
+	p.Stock = in.Stock
+	m.prices[p.ProductID] = in.Price
+}
+
+func timestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t, Valid: true}
+}
+
+func optionalText(s string, ok bool) pgtype.Text {
+	return pgtype.Text{String: s, Valid: ok}
+}
+
+// recordAudit menambah audit log; pemanggil memegang lock dan memanggilnya
+// sebelum mengubah data, supaya error tidak meninggalkan perubahan setengah jadi.
+func (m *Memory) recordAudit(ctx context.Context, action, entityType, entityID string, before, after any) error {
+	b, a, err := auditDiff(before, after)
+	if err != nil {
+		return err
+	}
+	actor := actorFrom(ctx)
+	var actorID pgtype.UUID
+	if actor.UserID != "" {
+		if actorID, err = parseUUID(actor.UserID); err != nil {
+			return err
+		}
+	}
+	m.lastAudit++
+	m.audit = append(m.audit, AuditEntry{
+		AuditID:    m.lastAudit,
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     b,
+		After:      a,
+		RequestID:  actor.RequestID,
+		IP:         actor.IP,
+		CreatedAt:  pgtype.Timestamptz{Time: m.now(), Valid: true},
+	})
+	return nil
+}
+
+// Produk
+
+type memoryProducts struct{ m *Memory }
+
+func (s memoryProducts) ListAvailable(ctx context.Context) ([]Product, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	var out []Product
+	for _, p := range s.m.products {
+		if p.Stock > 0 {
+			out = append(out, *p)
+		}
+	}
+	// Terbaru dulu, sama dengan ORDER BY created_at DESC
+	slices.SortFunc(out, func(a, b Product) int { return cmp.Compare(b.ProductID, a.ProductID) })
+	return out, nil
+}
+
+func (s memoryProducts) ListAll(ctx context.Context) ([]Product, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	var out []Product
+	for _, p := range s.m.products {
+		out = append(out, *p)
+	}
+	slices.SortFunc(out, func(a, b Product) int { return cmp.Compare(a.ProductID, b.ProductID) })
+	return out, nil
+}
+
+func (s memoryProducts) Get(ctx context.Context, id int32) (Product, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	// Seperti policy RLS products_public_read; Get hanya dipakai route publik
+	p, ok := s.m.products[id]
+	if !ok || p.Stock == 0 {
+		return Product{}, ErrProductNotFound
+	}
+	return *p, nil
+}
+
+func (s memoryProducts) Create(ctx context.Context, in ProductInput) (int32, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	price, _ := numeric(in.Price)
+	id := s.m.lastProduct + 1
+	if err := s.m.recordAudit(ctx, AuditProductCreate, AuditEntityProduct, strconv.Itoa(int(id)), nil, in.product(id, price)); err != nil {
+		return 0, err
+	}
+	return s.m.insertProduct(in), nil
+}
+
+func (s memoryProducts) Update(ctx context.Context, id int32, in ProductInput) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	p, ok := s.m.products[id]
+	if !ok {
+		return ErrProductNotFound
+	}
+	price, _ := numeric(in.Price)
+	if err := s.m.recordAudit(ctx, AuditProductUpdate, AuditEntityProduct, strconv.Itoa(int(id)), *p, in.product(id, price)); err != nil {
+		return err
+	}
+	s.m.setProduct(p, in)
+	return nil
+}
+
+func (s memoryProducts) Delete(ctx context.Context, id int32) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	p, ok := s.m.products[id]
+	if !ok {
+		return ErrProductNotFound
+	}
+	for _, o := range s.m.orders {
+		if o.productID == id {
+			return ErrProductInUse
+		}
+	}
+	if err := s.m.recordAudit(ctx, AuditProductDelete, AuditEntityProduct, strconv.Itoa(int(id)), *p, nil); err != nil {
+		return err
+	}
+	delete(s.m.products, id)
+	delete(s.m.prices, id)
+	return nil
+}
+
+// Order
+
+type memoryOrders struct{ m *Memory }
+
+func (s memoryOrders) List(ctx context.Context) ([]Order, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	var list []*memoryOrder
+	for _, o := range s.m.orders {
+		list = append(list, o)
+	}
+	slices.SortFunc(list, func(a, b *memoryOrder) int {
+		return cmp.Or(a.date.Compare(b.date), cmp.Compare(a.id, b.id))
+	})
+
+	out := make([]Order, 0, len(list))
+	for _, o := range list {
+		u, p := s.m.users[o.userID], s.m.products[o.productID]
+		total, _ := numeric(o.total)
+		order := Order{
+			OrderID:     o.id,
+			OrderDate:   timestamp(o.date),
+			TotalAmount: total,
+			Quantity:    o.quantity,
+			Status:      o.status,
+			ProductName: p.ProductName,
+			ImageUrl:    p.ImageUrl,
+		}
+		if u != nil {
+			order.CustomerName, order.PhoneNumber = u.Profile.FullName, u.Profile.PhoneNumber
+		}
+		if a := o.ship; a != nil {
+			order.ShipRecipientName = optionalText(a.RecipientName, true)
+			order.ShipPhoneNumber = optionalText(a.PhoneNumber, true)
+			order.ShipStreet = optionalText(a.Street, true)
+			order.ShipCity = optionalText(a.City, true)
+			order.ShipPostCode = optionalText(a.PostCode, true)
+		}
+		out = append(out, order)
+	}
+	return out, nil
+}
+
+func (s memoryOrders) Create(ctx context.Context, o NewOrder) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	u, ok := s.m.users[o.UserID]
+	if !ok || !u.Registered {
+		return ErrUserNotFound
+	}
+	if !u.EmailVerified {
+		return ErrEmailNotVerified
+	}
+	if u.Disabled {
+		return ErrAccountDisabled
+	}
+
+	var ship *Address
+	for _, a := range s.m.addresses {
+		if a.UserID != o.UserID {
+			continue
+		}
+		if (o.AddressID != nil && a.AddressID == *o.AddressID) || (o.AddressID == nil && a.IsDefault) {
+			snapshot := *a
+			ship = &snapshot
+		}
+	}
+	if o.AddressID != nil && ship == nil {
+		return ErrAddressNotFound
+	}
+	if ship == nil && u.Profile.Street != "" {
+		ship = &Address{
+			RecipientName: u.Profile.FullName,
+			PhoneNumber:   u.Profile.PhoneNumber,
+			Street:        u.Profile.Street,
+			City:          u.Profile.City,
+			PostCode:      u.Profile.PostCode,
+		}
+	}
+	if _, ok := s.m.products[o.ProductID]; !ok {
+		return ErrInvalidReference
+	}
+
+	s.m.lastOrder++
+	s.m.orders[s.m.lastOrder] = &memoryOrder{
+		id:        s.m.lastOrder,
+		userID:    o.UserID,
+		productID: o.ProductID,
+		quantity:  o.Quantity,
+		total:     o.TotalAmount,
+		status:    o.Status,
+		date:      s.m.now(),
+		ship:      ship,
+	}
+	return nil
+}
+
+func (s memoryOrders) UpdateStatus(ctx context.Context, orderID int32, status string) (StatusChange, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	o, ok := s.m.orders[orderID]
+	if !ok {
+		return StatusChange{}, ErrOrderNotFound
+	}
+	change := StatusChange{From: o.status, To: status}
+	if o.status == status {
+		return change, nil
+	}
+
+	// Cek stok dulu supaya gagal tanpa mengubah apa pun, seperti rollback
+	if status == "done" {
+		p := s.m.products[o.productID]
+		if p.Stock < o.quantity {
+			return StatusChange{}, ErrInsufficientStock
+		}
+	}
+	err := s.m.recordAudit(ctx, AuditOrderStatus, AuditEntityOrder, strconv.Itoa(int(orderID)),
+		map[string]string{"status": o.status}, map[string]string{"status": status})
+	if err != nil {
+		return StatusChange{}, err
+	}
+	if status == "done" {
+		p := s.m.products[o.productID]
+		p.Stock -= o.quantity
+		change.StockOut = p.Stock == 0
+	}
+	o.status = status
+	return change, nil
+}
+
+// User
+
+type memoryUsers struct{ m *Memory }
+
+// registered mengembalikan user yang punya profil; pemanggil memegang lock.
+func (s memoryUsers) registered(userID string) (*memoryUser, bool) {
+	u, ok := s.m.users[userID]
+	return u, ok && u.Registered
+}
+
+// requireActive sama dengan RequireActive tanpa lock, untuk method
+// self-service (seperti policy RLS users_update_own/addresses_own).
+func (s memoryUsers) requireActive(userID string) error {
+	if u, ok := s.m.users[userID]; ok && u.Disabled {
+		return ErrAccountDisabled
+	}
+	return nil
+}
+
+func (s memoryUsers) Profile(ctx context.Context, userID string) (Profile, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	u, ok := s.registered(userID)
+	if !ok {
+		return Profile{}, ErrProfileNotFound
+	}
+	if u.Disabled {
+		return Profile{}, ErrAccountDisabled
+	}
+	return u.Profile, nil
+}
+
+func (s memoryUsers) UpdateProfile(ctx context.Context, userID string, in ProfileUpdate) (Profile, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if err := s.requireActive(userID); err != nil {
+		return Profile{}, err
+	}
+
+	u, ok := s.registered(userID)
+	if !ok {
+		return Profile{}, ErrProfileNotFound
+	}
+	if in.Username != nil {
+		for _, other := range s.m.users {
+			if other != u && other.Registered && other.Profile.Username == *in.Username {
+				return Profile{}, ErrUsernameTaken
+			}
+		}
+		u.Profile.Username = *in.Username
+	}
+	if in.FullName != nil {
+		u.Profile.FullName = *in.FullName
+	}
+	if in.PhoneNumber != nil {
+		u.Profile.PhoneNumber = *in.PhoneNumber
+	}
+	return u.Profile, nil
+}
+
+func (s memoryUsers) RequireAdmin(ctx context.Context, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	u, ok := s.registered(userID)
+	switch {
+	case !ok:
+		return ErrProfileNotFound
+	case u.Disabled:
+		return ErrAccountDisabled
+	case u.Profile.Role != "admin":
+		return ErrNotAdmin
+	}
+	return nil
+}
+
+func (s memoryUsers) RequireActive(ctx context.Context, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	return s.requireActive(userID)
+}
+
+func (s memoryUsers) Addresses(ctx context.Context, userID string) ([]Address, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if err := s.requireActive(userID); err != nil {
+		return nil, err
+	}
+
+	out := []Address{}
+	for _, a := range s.m.addresses {
+		if a.UserID == userID {
+			out = append(out, *a)
+		}
+	}
+	// Default dulu, lalu urut ID
+	slices.SortFunc(out, func(a, b Address) int {
+		if a.IsDefault != b.IsDefault {
+			if a.IsDefault {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.AddressID, b.AddressID)
+	})
+	return out, nil
+}
+
+func (s memoryUsers) CreateAddress(ctx context.Context, userID string, in AddressInput) (Address, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if err := s.requireActive(userID); err != nil {
+		return Address{}, err
+	}
+
+	if _, ok := s.registered(userID); !ok {
+		return Address{}, ErrInvalidReference
+	}
+
+	count := 0
+	for _, a := range s.m.addresses {
+		if a.UserID == userID {
+			count++
+		}
+	}
+	isDefault := in.IsDefault || count == 0
+	if isDefault {
+		s.clearDefault(userID)
+	}
+
+	now := timestamp(s.m.now())
+	s.m.lastAddress++
+	a := &Address{AddressID: s.m.lastAddress, UserID: userID, IsDefault: isDefault, CreatedAt: now, UpdatedAt: now}
+	setAddress(a, in)
+	s.m.addresses[a.AddressID] = a
+	return *a, nil
+}
+
+func (s memoryUsers) clearDefault(userID string) {
+	for _, a := range s.m.addresses {
+		if a.UserID == userID {
+			a.IsDefault = false
+		}
+	}
+}
+
+func setAddress(a *Address, in AddressInput) {
+	a.Label = in.Label
+	a.RecipientName = in.RecipientName
+	a.PhoneNumber = in.PhoneNumber
+	a.Street = in.Street
+	a.City = in.City
+	a.PostCode = in.PostCode
+}
+
+// address mengembalikan alamat milik userID; pemanggil memegang lock.
+func (s memoryUsers) address(userID string, addressID int32) (*Address, error) {
+	a, ok := s.m.addresses[addressID]
+	if !ok || a.UserID != userID {
+		return nil, ErrAddressNotFound
+	}
+	return a, nil
+}
+
+func (s memoryUsers) UpdateAddress(ctx context.Context, userID string, addressID int32, in AddressInput) (Address, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if err := s.requireActive(userID); err != nil {
+		return Address{}, err
+	}
+
+	a, err := s.address(userID, addressID)
+	if err != nil {
+		return Address{}, err
+	}
+	setAddress(a, in)
+	a.UpdatedAt = timestamp(s.m.now())
+	return *a, nil
+}
+
+func (s memoryUsers) DeleteAddress(ctx context.Context, userID string, addressID int32) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if err := s.requireActive(userID); err != nil {
+		return err
+	}
+
+	if _, err := s.address(userID, addressID); err != nil {
+		return err
+	}
+	delete(s.m.addresses, addressID)
+	return nil
+}
+
+func (s memoryUsers) SetDefaultAddress(ctx context.Context, userID string, addressID int32) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if err := s.requireActive(userID); err != nil {
+		return err
+	}
+
+	a, err := s.address(userID, addressID)
+	if err != nil {
+		return err
+	}
+	s.clearDefault(userID)
+	a.IsDefault = true
+	return nil
+}
+
+func (s memoryUsers) Customers(ctx context.Context, q CustomerQuery) ([]Customer, int64, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	search := strings.ToLower(q.Search)
+	var matched []*memoryUser
+	for _, u := range s.m.users {
+		p := u.Profile
+		if !u.Registered {
+			continue
+		}
+		if search == "" || strings.Contains(strings.ToLower(p.FullName), search) ||
+			strings.Contains(strings.ToLower(p.Username), search) || strings.Contains(strings.ToLower(p.PhoneNumber), search) {
+			matched = append(matched, u)
+		}
+	}
+	// Terbaru dulu, sama dengan ORDER BY created_at DESC
+	slices.SortFunc(matched, func(a, b *memoryUser) int { return cmp.Compare(b.seq, a.seq) })
+
+	start := min(int((q.Page-1)*q.PageSize), len(matched))
+	end := min(start+int(q.PageSize), len(matched))
+	out := make([]Customer, 0, end-start)
+	for _, u := range matched[start:end] {
+		out = append(out, Customer{
+			UserID:      u.ID,
+			Username:    u.Profile.Username,
+			FullName:    u.Profile.FullName,
+			PhoneNumber: u.Profile.PhoneNumber,
+			Role:        u.Profile.Role,
+			City:        u.Profile.City,
+			DisabledAt:  pgtype.Timestamp{Time: u.createdAt, Valid: u.Disabled},
+			CreatedAt:   timestamp(u.createdAt),
+		})
+	}
+	return out, int64(len(matched)), nil
+}
+
+func (s memoryUsers) Customer(ctx context.Context, userID string) (CustomerDetail, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	u, ok := s.registered(userID)
+	if !ok {
+		return CustomerDetail{}, ErrCustomerNotFound
+	}
+
+	var count int64
+	var spend float64
+	for _, o := range s.m.orders {
+		if o.userID != userID {
+			continue
+		}
+		count++
+		if o.status == "done" {
+			spend += o.total
+		}
+	}
+	lifetime, _ := numeric(spend)
+
+	p := u.Profile
+	return CustomerDetail{
+		UserID:        u.ID,
+		Username:      p.Username,
+		FullName:      p.FullName,
+		PhoneNumber:   p.PhoneNumber,
+		Role:          p.Role,
+		Street:        p.Street,
+		City:          p.City,
+		PostCode:      p.PostCode,
+		DisabledAt:    pgtype.Timestamp{Time: u.createdAt, Valid: u.Disabled},
+		CreatedAt:     timestamp(u.createdAt),
+		OrderCount:    count,
+		LifetimeSpend: lifetime,
+	}, nil
+}
+
+func (s memoryUsers) SetRole(ctx context.Context, actorID, userID, role string) error {
+	if role != "admin" && sameUUID(userID, actorID) {
+		return ErrCannotDemoteSelf
+	}
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	u, ok := s.registered(userID)
+	if !ok {
+		return ErrCustomerNotFound
+	}
+	if u.Profile.Role == role {
+		return nil
+	}
+	err := s.m.recordAudit(ctx, AuditUserRole, AuditEntityUser, userID,
+		map[string]string{"role": u.Profile.Role}, map[string]string{"role": role})
+	if err != nil {
+		return err
+	}
+	u.Profile.Role = role
+	return nil
+}
+
+func (s memoryUsers) Disable(ctx context.Context, actorID, userID string) error {
+	if sameUUID(userID, actorID) {
+		return ErrCannotDisableSelf
+	}
+	return s.setDisabled(userID, true)
+}
+
+func (s memoryUsers) Enable(ctx context.Context, userID string) error {
+	return s.setDisabled(userID, false)
+}
+
+func (s memoryUsers) setDisabled(userID string, disabled bool) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	u, ok := s.registered(userID)
+	if !ok {
+		return ErrCustomerNotFound
+	}
+	u.Disabled = disabled
+	return nil
+}
+
+// Audit log
+
+type memoryAudit struct{ m *Memory }
+
+func (s memoryAudit) List(ctx context.Context, q AuditQuery) ([]AuditEntry, int64, error) {
+	var actorID pgtype.UUID
+	if q.ActorID != "" {
+		id, err := parseUUID(q.ActorID)
+		if err != nil {
+			return nil, 0, err
+		}
+		actorID = id
+	}
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	var matched []AuditEntry
+	for _, e := range s.m.audit {
+		t := e.CreatedAt.Time
+		switch {
+		case actorID.Valid && e.ActorID != actorID,
+			q.Action != "" && e.Action != q.Action,
+			q.EntityType != "" && e.EntityType != q.EntityType,
+			q.EntityID != "" && e.EntityID != q.EntityID,
+			!q.Since.IsZero() && t.Before(q.Since),
+			!q.Until.IsZero() && !t.Before(q.Until):
+			continue
+		}
+		matched = append(matched, e)
+	}
+	// Terbaru dulu, sama dengan ORDER BY created_at DESC, audit_id DESC
+	slices.SortFunc(matched, func(a, b AuditEntry) int {
+		return cmp.Or(b.CreatedAt.Time.Compare(a.CreatedAt.Time), cmp.Compare(b.AuditID, a.AuditID))
+	})
+
+	start := min(int((q.Page-1)*q.PageSize), len(matched))
+	end := min(start+int(q.PageSize), len(matched))
+	return slices.Clone(matched[start:end]), int64(len(matched)), nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"backend/pkg/apierror"
+	"backend/pkg/app/admindb"
+	"backend/pkg/database"
+)
+
+type postgresOrders struct {
+	db    *pgxpool.Pool
+	admin *admindb.Queries
+}
+
+func (s *postgresOrders) List(ctx context.Context) ([]Order, error) {
+	var rows []admindb.ListOrdersRow
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		rows, err = s.admin.WithTx(tx).ListOrders(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]Order, len(rows))
+	for i, o := range rows {
+		orders[i] = Order(o)
+	}
+	return orders, nil
+}
+
+func (s *postgresOrders) Create(ctx context.Context, o NewOrder) error {
+	userID, err := parseUUID(o.UserID)
+	if err != nil {
+		return err
+	}
+
+	// Order hanya boleh dibuat untuk user aktif yang email-nya sudah terverifikasi.
+	// auth.users tidak bisa dibaca role authenticated, jadi dicek di luar Scoped.
+	var verified, disabled bool
+	err = s.db.QueryRow(ctx, "SELECT au.email_confirmed_at IS NOT NULL, u.disabled_at IS NOT NULL FROM auth.users au JOIN users u ON u.user_id = au.id WHERE au.id = $1", userID).Scan(&verified, &disabled)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrUserNotFound
+		}
+		return err
+	}
+	if !verified {
+		return ErrEmailNotVerified
+	}
+	if disabled {
+		return ErrAccountDisabled
+	}
+
+	total, err := numeric(o.TotalAmount)
+	if err != nil {
+		return apierror.Validation(apierror.FieldError{Field: "total_amount", Code: "invalid_number", Message: "Must be a valid amount"})
+	}
+
+	params := admindb.CreateOrderParams{
+		UserID:      userID,
+		ProductID:   o.ProductID,
+		Quantity:    o.Quantity,
+		TotalAmount: total,
+		Status:      o.Status,
+	}
+
+	return database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		qtx := s.admin.WithTx(tx)
+
+		// Snapshot alamat pengiriman: address_id yang dipilih, alamat default user,
+		// atau alamat di profil kalau buku alamatnya masih kosong
+		var address admindb.Address
+		if o.AddressID != nil {
+			address, err = qtx.GetUserAddress(ctx, admindb.GetUserAddressParams{AddressID: *o.AddressID, UserID: userID})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					err = ErrAddressNotFound
+				}
+				return err
+			}
+		} else {
+			address, err = qtx.GetDefaultAddress(ctx, userID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				p, err := qtx.GetProfileShipping(ctx, userID)
+				if err != nil {
+					return err
+				}
+				if p.Street != "" {
+					params.ShipRecipientName = pgtype.Text{String: p.FullName, Valid: true}
+					params.ShipPhoneNumber = pgtype.Text{String: p.PhoneNumber, Valid: true}
+					params.ShipStreet = pgtype.Text{String: p.Street, Valid: true}
+					params.ShipCity = pgtype.Text{String: p.City, Valid: true}
+					params.ShipPostCode = pgtype.Text{String: p.PostCode, Valid: true}
+				}
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if address.AddressID != 0 {
+			params.AddressID = pgtype.Int4{Int32: address.AddressID, Valid: true}
+			params.ShipRecipientName = pgtype.Text{String: address.RecipientName, Valid: true}
+			params.ShipPhoneNumber = pgtype.Text{String: address.PhoneNumber, Valid: true}
+			params.ShipStreet = pgtype.Text{String: address.Street, Valid: true}
+			params.ShipCity = pgtype.Text{String: address.City, Valid: true}
+			params.ShipPostCode = pgtype.Text{String: address.PostCode, Valid: true}
+		}
+
+		return qtx.CreateOrder(ctx, params)
+	})
+}
+
+func (s *postgresOrders) UpdateStatus(ctx context.Context, orderID int32, status string) (StatusChange, error) {
+	change := StatusChange{To: status}
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		qtx := s.admin.WithTx(tx)
+
+		prev, err := qtx.GetOrderStatusForUpdate(ctx, orderID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = ErrOrderNotFound
+			}
+			return err
+		}
+		change.From = prev
+		// Status yang sama tidak mengubah apa pun: stok tidak dikurangi lagi
+		// dan tidak ada audit log
+		if prev == status {
+			return nil
+		}
+
+		if err := qtx.UpdateOrderStatus(ctx, admindb.UpdateOrderStatusParams{OrderID: orderID, Status: status}); err != nil {
+			return err
+		}
+		err = recordAudit(ctx, qtx, AuditOrderStatus, AuditEntityOrder, strconv.Itoa(int(orderID)),
+			map[string]string{"status": prev}, map[string]string{"status": status})
+		if err != nil {
+			return err
+		}
+		if status != "done" {
+			return nil
+		}
+
+		// CHECK (stock >= 0) membatalkan seluruh transaksi kalau stok kurang
+		info, err := qtx.GetOrderQuantityAndProduct(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		stock, err := qtx.DecreaseProductStock(ctx, admindb.DecreaseProductStockParams{
+			ProductID: info.ProductID,
+			Stock:     info.Quantity,
+		})
+		if err != nil {
+			return err
+		}
+		change.StockOut = stock == 0
+		return nil
+	})
+	return change, err
+}
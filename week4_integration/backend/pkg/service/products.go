@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"backend/pkg/app/admindb"
+	"backend/pkg/app/publicdb"
+	"backend/pkg/database"
+)
+
+// NewPostgres membuat semua service di atas pool yang sama. Query berjalan
+// lewat database.Scoped, jadi policy RLS ikut membatasi hasilnya sesuai claim
+// di context (lihat database.WithClaims).
+func NewPostgres(db *pgxpool.Pool) Services {
+	return Services{
+		Products: &postgresProducts{db: db, public: publicdb.New(db), admin: admindb.New(db)},
+		Orders:   &postgresOrders{db: db, admin: admindb.New(db)},
+		Users:    &postgresUsers{db: db, public: publicdb.New(db), admin: admindb.New(db)},
+		Audit:    &postgresAudit{db: db, admin: admindb.New(db)},
+	}
+}
+
+type postgresProducts struct {
+	db     *pgxpool.Pool
+	public *publicdb.Queries
+	admin  *admindb.Queries
+}
+
+func (s *postgresProducts) ListAvailable(ctx context.Context) ([]Product, error) {
+	var rows []publicdb.ListAvailableProductsRow
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		rows, err = s.public.WithTx(tx).ListAvailableProducts(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	products := make([]Product, len(rows))
+	for i, p := range rows {
+		products[i] = Product{
+			ProductID:   p.ProductID,
+			ImageUrl:    p.ImageUrl,
+			ProductName: p.ProductName,
+			Category:    p.Category,
+			UnitPrice:   p.UnitPrice,
+			Stock:       p.Stock,
+		}
+	}
+	return products, nil
+}
+
+func (s *postgresProducts) ListAll(ctx context.Context) ([]Product, error) {
+	var rows []admindb.ListAllProductsAdminRow
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		rows, err = s.admin.WithTx(tx).ListAllProductsAdmin(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	products := make([]Product, len(rows))
+	for i, p := range rows {
+		products[i] = Product{
+			ProductID:   p.ProductID,
+			ImageUrl:    p.ImageUrl,
+			ProductName: p.ProductName,
+			Category:    p.Category,
+			Description: p.Description,
+			UnitPrice:   p.UnitPrice,
+			Stock:       p.Stock,
+		}
+	}
+	return products, nil
+}
+
+func (s *postgresProducts) Get(ctx context.Context, id int32) (Product, error) {
+	var p publicdb.GetProductDetailRow
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		p, err = s.public.WithTx(tx).GetProductDetail(ctx, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrProductNotFound
+		}
+		return Product{}, err
+	}
+	return Product{
+		ProductID:   p.ProductID,
+		ImageUrl:    p.ImageUrl,
+		ProductName: p.ProductName,
+		Category:    p.Category,
+		Description: p.Description,
+		UnitPrice:   p.UnitPrice,
+		Stock:       p.Stock,
+	}, nil
+}
+
+func (s *postgresProducts) Create(ctx context.Context, in ProductInput) (int32, error) {
+	price, err := numeric(in.Price)
+	if err != nil {
+		return 0, err
+	}
+	var id int32
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		qtx := s.admin.WithTx(tx)
+		id, err = qtx.CreateProduct(ctx, admindb.CreateProductParams{
+			ProductName: in.Name,
+			Category:    in.Category,
+			Description: in.Description,
+			UnitPrice:   price,
+			ImageUrl:    in.ImageUrl,
+			Stock:       in.Stock,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, qtx, AuditProductCreate, AuditEntityProduct, strconv.Itoa(int(id)), nil, in.product(id, price))
+	})
+	return id, err
+}
+
+func (s *postgresProducts) Update(ctx context.Context, id int32, in ProductInput) error {
+	price, err := numeric(in.Price)
+	if err != nil {
+		return err
+	}
+	return database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		qtx := s.admin.WithTx(tx)
+		prev, err := qtx.GetProductForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = ErrProductNotFound
+			}
+			return err
+		}
+		err = qtx.UpdateProduct(ctx, admindb.UpdateProductParams{
+			ProductID:   id,
+			ProductName: in.Name,
+			Category:    in.Category,
+			Description: in.Description,
+			UnitPrice:   price,
+			ImageUrl:    in.ImageUrl,
+			Stock:       in.Stock,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, qtx, AuditProductUpdate, AuditEntityProduct, strconv.Itoa(int(id)), productFromRow(prev), in.product(id, price))
+	})
+}
+
+// Delete gagal dengan foreign key violation (resource_in_use) kalau produk
+// masih dipakai order.
+func (s *postgresProducts) Delete(ctx context.Context, id int32) error {
+	return database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		qtx := s.admin.WithTx(tx)
+		prev, err := qtx.GetProductForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = ErrProductNotFound
+			}
+			return err
+		}
+		if err := qtx.DeleteProduct(ctx, id); err != nil {
+			if isForeignKeyViolation(err) {
+				err = ErrProductInUse.Wrap(err)
+			}
+			return err
+		}
+		return recordAudit(ctx, qtx, AuditProductDelete, AuditEntityProduct, strconv.Itoa(int(id)), productFromRow(prev), nil)
+	})
+}
+
+func productFromRow(p admindb.Product) Product {
+	return Product{
+		ProductID:   p.ProductID,
+		ImageUrl:    p.ImageUrl,
+		ProductName: p.ProductName,
+		Category:    p.Category,
+		Description: p.Description,
+		UnitPrice:   p.UnitPrice,
+		Stock:       p.Stock,
+	}
+}
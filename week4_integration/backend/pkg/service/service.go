@@ -0,0 +1,369 @@
+// Package service berisi aturan bisnis toko (produk, order, user) di belakang
+// interface, supaya handler HTTP tidak bergantung langsung pada sqlc atau
+// pgxpool dan bisa dites tanpa database.
+//
+// Implementasi Postgres (NewPostgres) memegang transaksi dan query sqlc.
+// Memory (NewMemory) adalah fake di memori untuk test, dengan aturan yang
+// sama: stok tidak boleh minus, username unik, produk yang masih dipakai order
+// tidak bisa dihapus, dan seterusnya.
+//
+// Tipe domain memakai pgtype untuk uang dan waktu supaya JSON yang dikirim API
+// sama persis dengan sebelum ada layer ini.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"backend/pkg/apierror"
+)
+
+// Error domain. Code-nya bagian dari kontrak API, jangan diganti setelah dirilis.
+var (
+	ErrProfileNotFound  = apierror.Forbidden("user_not_registered", "User is not registered")
+	ErrAccountDisabled  = apierror.Forbidden("account_disabled", "Account is disabled")
+	ErrEmailNotVerified = apierror.Forbidden("email_not_verified", "Email address is not verified")
+	ErrNotAdmin         = apierror.Forbidden("admin_required", "Admin role required")
+
+	ErrProductNotFound  = apierror.NotFound("product_not_found", "Product not found")
+	ErrOrderNotFound    = apierror.NotFound("order_not_found", "Order not found")
+	ErrAddressNotFound  = apierror.NotFound("address_not_found", "Address not found")
+	ErrCustomerNotFound = apierror.NotFound("customer_not_found", "Customer not found")
+	ErrUserNotFound     = apierror.NotFound("user_not_found", "User not found")
+
+	ErrCannotDemoteSelf  = apierror.Conflict("cannot_demote_self", "You cannot remove your own admin role")
+	ErrCannotDisableSelf = apierror.Conflict("cannot_disable_self", "You cannot disable your own account")
+
+	ErrInvalidUUID = apierror.BadRequest(apierror.CodeInvalidID, "ID must be a valid UUID").WithKey("error.invalid_id.uuid", nil)
+
+	// Sama dengan hasil pemetaan constraint Postgres di apierror, dipakai Memory
+	ErrInsufficientStock = apierror.Conflict(apierror.CodeInsufficientStock, "Not enough stock for this product")
+	ErrUsernameTaken     = apierror.Conflict(apierror.CodeAlreadyExists, "Username is already taken").WithKey("error.already_exists.username", nil)
+	ErrProductInUse      = apierror.Conflict(apierror.CodeResourceInUse, "Resource is still referenced by other records")
+	ErrInvalidReference  = apierror.New(http.StatusUnprocessableEntity, apierror.CodeInvalidReference, "Referenced resource does not exist")
+)
+
+// Services mengelompokkan semua service yang dibutuhkan router.
+type Services struct {
+	Products ProductService
+	Orders   OrderService
+	Users    UserService
+	Audit    AuditService
+}
+
+// Produk
+
+type Product struct {
+	ProductID   int32          `json:"product_id"`
+	ImageUrl    string         `json:"image_url"`
+	ProductName string         `json:"product_name"`
+	Category    string         `json:"category"`
+	Description string         `json:"description"`
+	UnitPrice   pgtype.Numeric `json:"unit_price"`
+	Stock       int32          `json:"stock"`
+}
+
+// ProductInput dipakai untuk create dan update (update mengganti semua field).
+type ProductInput struct {
+	Name        string
+	Category    string
+	Description string
+	Price       float64
+	ImageUrl    string
+	Stock       int32
+}
+
+func (in ProductInput) product(id int32, price pgtype.Numeric) Product {
+	return Product{
+		ProductID:   id,
+		ImageUrl:    in.ImageUrl,
+		ProductName: in.Name,
+		Category:    in.Category,
+		Description: in.Description,
+		UnitPrice:   price,
+		Stock:       in.Stock,
+	}
+}
+
+type ProductService interface {
+	// ListAvailable hanya mengembalikan produk yang stoknya masih ada, tanpa
+	// Description (cukup untuk halaman katalog).
+	ListAvailable(ctx context.Context) ([]Product, error)
+	// ListAll untuk admin, termasuk produk dengan stok 0.
+	ListAll(ctx context.Context) ([]Product, error)
+	// Get mengembalikan ErrProductNotFound untuk produk stok 0 kalau pemanggil
+	// bukan admin (policy RLS products_public_read).
+	Get(ctx context.Context, id int32) (Product, error)
+	Create(ctx context.Context, in ProductInput) (int32, error)
+	// Update dan Delete mengembalikan ErrProductNotFound kalau produk tidak ada.
+	Update(ctx context.Context, id int32, in ProductInput) error
+	Delete(ctx context.Context, id int32) error
+}
+
+// Order
+
+type Order struct {
+	OrderID           int32            `json:"order_id"`
+	OrderDate         pgtype.Timestamp `json:"order_date"`
+	TotalAmount       pgtype.Numeric   `json:"total_amount"`
+	Quantity          int32            `json:"quantity"`
+	Status            string           `json:"status"`
+	CustomerName      string           `json:"customer_name"`
+	PhoneNumber       string           `json:"phone_number"`
+	ProductName       string           `json:"product_name"`
+	ImageUrl          string           `json:"image_url"`
+	ShipRecipientName pgtype.Text      `json:"ship_recipient_name"`
+	ShipPhoneNumber   pgtype.Text      `json:"ship_phone_number"`
+	ShipStreet        pgtype.Text      `json:"ship_street"`
+	ShipCity          pgtype.Text      `json:"ship_city"`
+	ShipPostCode      pgtype.Text      `json:"ship_post_code"`
+}
+
+// NewOrder adalah order yang dibuat admin. AddressID nil berarti alamat
+// default user; alamat pengiriman disalin ke order saat dibuat.
+type NewOrder struct {
+	UserID      string
+	ProductID   int32
+	Quantity    int32
+	TotalAmount float64
+	Status      string
+	AddressID   *int32
+}
+
+// StatusChange adalah hasil UpdateStatus, untuk metrics dan notifikasi.
+type StatusChange struct {
+	From, To string
+	// StockOut true kalau order ini menghabiskan stok produknya.
+	StockOut bool
+}
+
+type OrderService interface {
+	List(ctx context.Context) ([]Order, error)
+	// Create hanya untuk user aktif yang email-nya sudah terverifikasi.
+	Create(ctx context.Context, o NewOrder) error
+	// UpdateStatus mengurangi stok produk kalau status baru "done", dalam
+	// transaksi yang sama dengan perubahan status. Status yang sama dengan
+	// sebelumnya tidak mengubah apa pun.
+	UpdateStatus(ctx context.Context, orderID int32, status string) (StatusChange, error)
+}
+
+// User, alamat, dan customer
+
+type Profile struct {
+	UserID      string
+	Username    string
+	FullName    string
+	PhoneNumber string
+	Role        string
+	Street      string
+	City        string
+	PostCode    string
+}
+
+// ProfileUpdate hanya mengubah field yang tidak nil.
+type ProfileUpdate struct {
+	Username    *string
+	FullName    *string
+	PhoneNumber *string
+}
+
+type Address struct {
+	AddressID     int32            `json:"address_id"`
+	UserID        string           `json:"user_id"`
+	Label         string           `json:"label"`
+	RecipientName string           `json:"recipient_name"`
+	PhoneNumber   string           `json:"phone_number"`
+	Street        string           `json:"street"`
+	City          string           `json:"city"`
+	PostCode      string           `json:"post_code"`
+	IsDefault     bool             `json:"is_default"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+}
+
+type AddressInput struct {
+	Label         string
+	RecipientName string
+	PhoneNumber   string
+	Street        string
+	City          string
+	PostCode      string
+	// IsDefault hanya dipakai saat create; alamat pertama selalu jadi default.
+	IsDefault bool
+}
+
+type Customer struct {
+	UserID      string           `json:"user_id"`
+	Username    string           `json:"username"`
+	FullName    string           `json:"full_name"`
+	PhoneNumber string           `json:"phone_number"`
+	Role        string           `json:"role"`
+	City        string           `json:"city"`
+	DisabledAt  pgtype.Timestamp `json:"disabled_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type CustomerDetail struct {
+	UserID        string           `json:"user_id"`
+	Username      string           `json:"username"`
+	FullName      string           `json:"full_name"`
+	PhoneNumber   string           `json:"phone_number"`
+	Role          string           `json:"role"`
+	Street        string           `json:"street"`
+	City          string           `json:"city"`
+	PostCode      string           `json:"post_code"`
+	DisabledAt    pgtype.Timestamp `json:"disabled_at"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	OrderCount    int64            `json:"order_count"`
+	LifetimeSpend pgtype.Numeric   `json:"lifetime_spend"`
+}
+
+// CustomerQuery mencari di nama, username, dan nomor telepon. Page mulai dari 1.
+type CustomerQuery struct {
+	Search   string
+	Page     int32
+	PageSize int32
+}
+
+type UserService interface {
+	// Profile mengembalikan ErrProfileNotFound kalau user belum punya baris
+	// users, dan ErrAccountDisabled kalau akunnya dinonaktifkan.
+	Profile(ctx context.Context, userID string) (Profile, error)
+	UpdateProfile(ctx context.Context, userID string, in ProfileUpdate) (Profile, error)
+	// RequireAdmin mengecek user terdaftar, aktif, dan ber-role admin.
+	RequireAdmin(ctx context.Context, userID string) error
+	// RequireActive hanya menolak akun yang dinonaktifkan (ErrAccountDisabled).
+	// User yang belum punya profil lolos; handler yang butuh profil mengeceknya.
+	RequireActive(ctx context.Context, userID string) error
+
+	Addresses(ctx context.Context, userID string) ([]Address, error)
+	CreateAddress(ctx context.Context, userID string, in AddressInput) (Address, error)
+	UpdateAddress(ctx context.Context, userID string, addressID int32, in AddressInput) (Address, error)
+	DeleteAddress(ctx context.Context, userID string, addressID int32) error
+	SetDefaultAddress(ctx context.Context, userID string, addressID int32) error
+
+	Customers(ctx context.Context, q CustomerQuery) ([]Customer, int64, error)
+	Customer(ctx context.Context, userID string) (CustomerDetail, error)
+	// SetRole dan Disable menolak perubahan pada akun actor sendiri, supaya
+	// admin tidak mengunci dirinya keluar dari /api/admin.
+	SetRole(ctx context.Context, actorID, userID, role string) error
+	Disable(ctx context.Context, actorID, userID string) error
+	Enable(ctx context.Context, userID string) error
+}
+
+// Audit log
+
+// Action audit log. Sama seperti code error, nilainya dipakai filter client.
+const (
+	AuditProductCreate = "product.create"
+	AuditProductUpdate = "product.update"
+	AuditProductDelete = "product.delete"
+	AuditOrderStatus   = "order.status"
+	AuditUserRole      = "user.role"
+)
+
+// Jenis entity di audit log.
+const (
+	AuditEntityProduct = "product"
+	AuditEntityOrder   = "order"
+	AuditEntityUser    = "user"
+)
+
+// Actor adalah admin di balik perubahan. AdminOnly memasangnya di context
+// (WithActor), lalu service mencatatnya di audit log bersama perubahan itu.
+type Actor struct {
+	UserID    string
+	RequestID string
+	IP        string
+}
+
+type actorKey struct{}
+
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+func actorFrom(ctx context.Context) Actor {
+	a, _ := ctx.Value(actorKey{}).(Actor)
+	return a
+}
+
+// AuditEntry menyimpan hanya field yang berubah di Before/After. Create tidak
+// punya Before dan delete tidak punya After (null).
+type AuditEntry struct {
+	AuditID    int64              `json:"audit_id"`
+	ActorID    pgtype.UUID        `json:"actor_id"`
+	Action     string             `json:"action"`
+	EntityType string             `json:"entity_type"`
+	EntityID   string             `json:"entity_id"`
+	Before     json.RawMessage    `json:"before"`
+	After      json.RawMessage    `json:"after"`
+	RequestID  string             `json:"request_id"`
+	IP         string             `json:"ip"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+// AuditQuery memfilter audit log; field kosong berarti tanpa filter. Until
+// eksklusif. Page mulai dari 1.
+type AuditQuery struct {
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	Since      time.Time
+	Until      time.Time
+	Page       int32
+	PageSize   int32
+}
+
+type AuditService interface {
+	// List mengurutkan dari yang terbaru.
+	List(ctx context.Context, q AuditQuery) ([]AuditEntry, int64, error)
+}
+
+func parseUUID(s string) (pgtype.UUID, error) {
+	var id pgtype.UUID
+	if err := id.Scan(s); err != nil {
+		return id, ErrInvalidUUID.Wrap(err)
+	}
+	return id, nil
+}
+
+// isForeignKeyViolation dipakai jalur delete untuk membedakan "masih dirujuk"
+// dari pemetaan umum apierror (invalid_reference).
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
+
+// sameUUID membandingkan dua UUID setelah di-parse, jadi huruf besar atau
+// tanpa tanda hubung tetap dianggap sama.
+func sameUUID(a, b string) bool {
+	x, err := parseUUID(a)
+	if err != nil {
+		return false
+	}
+	y, err := parseUUID(b)
+	return err == nil && x.Bytes == y.Bytes
+}
+
+func textParam(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+// numeric mengubah angka dari request JSON ke NUMERIC dengan dua desimal.
+func numeric(v float64) (pgtype.Numeric, error) {
+	var n pgtype.Numeric
+	err := n.Scan(fmt.Sprintf("%.2f", v))
+	return n, err
+}
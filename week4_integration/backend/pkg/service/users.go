@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"backend/pkg/app/admindb"
+	"backend/pkg/app/publicdb"
+	"backend/pkg/database"
+)
+
+type postgresUsers struct {
+	db     *pgxpool.Pool
+	public *publicdb.Queries
+	admin  *admindb.Queries
+}
+
+func (s *postgresUsers) Profile(ctx context.Context, userID string) (Profile, error) {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	var p publicdb.GetUserProfileRow
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		p, err = s.public.WithTx(tx).GetUserProfile(ctx, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrProfileNotFound
+		}
+		return Profile{}, err
+	}
+	if p.DisabledAt.Valid {
+		return Profile{}, ErrAccountDisabled
+	}
+
+	return Profile{
+		UserID:      userID,
+		Username:    p.Username,
+		FullName:    p.FullName,
+		PhoneNumber: p.PhoneNumber,
+		Role:        p.Role,
+		Street:      p.Street,
+		City:        p.City,
+		PostCode:    p.PostCode,
+	}, nil
+}
+
+func (s *postgresUsers) UpdateProfile(ctx context.Context, userID string, in ProfileUpdate) (Profile, error) {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	var p publicdb.UpdateUserProfileRow
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		p, err = s.public.WithTx(tx).UpdateUserProfile(ctx, publicdb.UpdateUserProfileParams{
+			Username:    textParam(in.Username),
+			FullName:    textParam(in.FullName),
+			PhoneNumber: textParam(in.PhoneNumber),
+			UserID:      id,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrProfileNotFound
+		}
+		return Profile{}, err
+	}
+
+	return Profile{
+		UserID:      userID,
+		Username:    p.Username,
+		FullName:    p.FullName,
+		PhoneNumber: p.PhoneNumber,
+		Role:        p.Role,
+		Street:      p.Street,
+		City:        p.City,
+		PostCode:    p.PostCode,
+	}, nil
+}
+
+func (s *postgresUsers) RequireAdmin(ctx context.Context, userID string) error {
+	var role string
+	var disabled bool
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "SELECT role, disabled_at IS NOT NULL FROM users WHERE user_id = $1", userID).Scan(&role, &disabled)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrProfileNotFound
+		}
+		return err
+	}
+	if disabled {
+		return ErrAccountDisabled
+	}
+	if role != "admin" {
+		return ErrNotAdmin
+	}
+	return nil
+}
+
+func (s *postgresUsers) RequireActive(ctx context.Context, userID string) error {
+	var disabled bool
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "SELECT disabled_at IS NOT NULL FROM users WHERE user_id = $1", userID).Scan(&disabled)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if disabled {
+		return ErrAccountDisabled
+	}
+	return nil
+}
+
+// Alamat
+
+func toAddress(a publicdb.Address) Address {
+	return Address{
+		AddressID:     a.AddressID,
+		UserID:        a.UserID.String(),
+		Label:         a.Label,
+		RecipientName: a.RecipientName,
+		PhoneNumber:   a.PhoneNumber,
+		Street:        a.Street,
+		City:          a.City,
+		PostCode:      a.PostCode,
+		IsDefault:     a.IsDefault,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
+}
+
+func (s *postgresUsers) Addresses(ctx context.Context, userID string) ([]Address, error) {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []publicdb.Address
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		rows, err = s.public.WithTx(tx).ListUserAddresses(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]Address, len(rows))
+	for i, a := range rows {
+		addresses[i] = toAddress(a)
+	}
+	return addresses, nil
+}
+
+func (s *postgresUsers) CreateAddress(ctx context.Context, userID string, in AddressInput) (Address, error) {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return Address{}, err
+	}
+
+	var address publicdb.Address
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		qtx := s.public.WithTx(tx)
+
+		// Alamat pertama otomatis jadi default
+		count, err := qtx.CountUserAddresses(ctx, id)
+		if err != nil {
+			return err
+		}
+		isDefault := in.IsDefault || count == 0
+
+		if isDefault {
+			if err := qtx.ClearDefaultAddress(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		address, err = qtx.CreateAddress(ctx, publicdb.CreateAddressParams{
+			UserID:        id,
+			Label:         in.Label,
+			RecipientName: in.RecipientName,
+			PhoneNumber:   in.PhoneNumber,
+			Street:        in.Street,
+			City:          in.City,
+			PostCode:      in.PostCode,
+			IsDefault:     isDefault,
+		})
+		return err
+	})
+	if err != nil {
+		return Address{}, err
+	}
+	return toAddress(address), nil
+}
+
+func (s *postgresUsers) UpdateAddress(ctx context.Context, userID string, addressID int32, in AddressInput) (Address, error) {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return Address{}, err
+	}
+
+	var address publicdb.Address
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		address, err = s.public.WithTx(tx).UpdateAddress(ctx, publicdb.UpdateAddressParams{
+			AddressID:     addressID,
+			UserID:        id,
+			Label:         in.Label,
+			RecipientName: in.RecipientName,
+			PhoneNumber:   in.PhoneNumber,
+			Street:        in.Street,
+			City:          in.City,
+			PostCode:      in.PostCode,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrAddressNotFound
+		}
+		return Address{}, err
+	}
+	return toAddress(address), nil
+}
+
+func (s *postgresUsers) DeleteAddress(ctx context.Context, userID string, addressID int32) error {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	var rows int64
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		rows, err = s.public.WithTx(tx).DeleteAddress(ctx, publicdb.DeleteAddressParams{AddressID: addressID, UserID: id})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAddressNotFound
+	}
+	return nil
+}
+
+func (s *postgresUsers) SetDefaultAddress(ctx context.Context, userID string, addressID int32) error {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	return database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		qtx := s.public.WithTx(tx)
+
+		if err := qtx.ClearDefaultAddress(ctx, id); err != nil {
+			return err
+		}
+		rows, err := qtx.SetDefaultAddress(ctx, publicdb.SetDefaultAddressParams{AddressID: addressID, UserID: id})
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrAddressNotFound
+		}
+		return nil
+	})
+}
+
+// Customer (admin)
+
+func (s *postgresUsers) Customers(ctx context.Context, q CustomerQuery) ([]Customer, int64, error) {
+	var rows []admindb.ListCustomersRow
+	var total int64
+	err := database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		qtx := s.admin.WithTx(tx)
+		rows, err = qtx.ListCustomers(ctx, admindb.ListCustomersParams{
+			Search:     q.Search,
+			PageSize:   q.PageSize,
+			PageOffset: (q.Page - 1) * q.PageSize,
+		})
+		if err != nil {
+			return err
+		}
+		total, err = qtx.CountCustomers(ctx, q.Search)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	customers := make([]Customer, len(rows))
+	for i, c := range rows {
+		customers[i] = Customer{
+			UserID:      c.UserID.String(),
+			Username:    c.Username,
+			FullName:    c.FullName,
+			PhoneNumber: c.PhoneNumber,
+			Role:        c.Role,
+			City:        c.City,
+			DisabledAt:  c.DisabledAt,
+			CreatedAt:   c.CreatedAt,
+		}
+	}
+	return customers, total, nil
+}
+
+func (s *postgresUsers) Customer(ctx context.Context, userID string) (CustomerDetail, error) {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return CustomerDetail{}, err
+	}
+
+	var c admindb.GetCustomerDetailRow
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		c, err = s.admin.WithTx(tx).GetCustomerDetail(ctx, id)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = ErrCustomerNotFound
+		}
+		return CustomerDetail{}, err
+	}
+	return CustomerDetail{
+		UserID:        c.UserID.String(),
+		Username:      c.Username,
+		FullName:      c.FullName,
+		PhoneNumber:   c.PhoneNumber,
+		Role:          c.Role,
+		Street:        c.Street,
+		City:          c.City,
+		PostCode:      c.PostCode,
+		DisabledAt:    c.DisabledAt,
+		CreatedAt:     c.CreatedAt,
+		OrderCount:    c.OrderCount,
+		LifetimeSpend: c.LifetimeSpend,
+	}, nil
+}
+
+func (s *postgresUsers) SetRole(ctx context.Context, actorID, userID, role string) error {
+	if role != "admin" && sameUUID(userID, actorID) {
+		return ErrCannotDemoteSelf
+	}
+	id, err := parseUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	return database.Scoped(ctx, s.db, func(tx pgx.Tx) error {
+		qtx := s.admin.WithTx(tx)
+		prev, err := qtx.GetCustomerRoleForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				err = ErrCustomerNotFound
+			}
+			return err
+		}
+		// Role yang sama tidak mengubah apa pun dan tidak dicatat di audit log
+		if prev == role {
+			return nil
+		}
+		if _, err := qtx.UpdateCustomerRole(ctx, admindb.UpdateCustomerRoleParams{UserID: id, Role: role}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, qtx, AuditUserRole, AuditEntityUser, userID,
+			map[string]string{"role": prev}, map[string]string{"role": role})
+	})
+}
+
+func (s *postgresUsers) Disable(ctx context.Context, actorID, userID string) error {
+	if sameUUID(userID, actorID) {
+		return ErrCannotDisableSelf
+	}
+	id, err := parseUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	var rows int64
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		rows, err = s.admin.WithTx(tx).DisableCustomer(ctx, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrCustomerNotFound
+	}
+	return nil
+}
+
+func (s *postgresUsers) Enable(ctx context.Context, userID string) error {
+	id, err := parseUUID(userID)
+	if err != nil {
+		return err
+	}
+
+	var rows int64
+	err = database.Scoped(ctx, s.db, func(tx pgx.Tx) (err error) {
+		rows, err = s.admin.WithTx(tx).EnableCustomer(ctx, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrCustomerNotFound
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Package validate memeriksa struct request berdasarkan tag `validate`, mis.
+//
+//	Name  string  `json:"name" validate:"required,max=100"`
+//	Price float64 `json:"price" validate:"gt=0"`
+//
+// Semua field diperiksa sekaligus supaya client bisa menampilkan semua
+// kesalahan dalam satu kali submit. Nama field diambil dari tag json.
+//
+// Aturan yang didukung:
+//   - required: string tidak kosong (setelah trim), pointer tidak nil, angka bukan 0
+//   - omitempty: aturan berikutnya dilewati kalau nilainya kosong
+//   - min=N, max=N: panjang string (karakter) / slice, atau nilai angka
+//   - gt=N: angka lebih besar dari N
+//   - oneof=a b c: nilai harus salah satu dari daftar
+//   - email, uuid, url: format string
+//
+// Pointer yang nil dilewati kecuali ada required, jadi cocok untuk PATCH.
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"backend/pkg/apierror"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// Struct mengembalikan semua pelanggaran aturan pada v (struct atau pointer ke struct).
+func Struct(v any) []apierror.FieldError {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []apierror.FieldError
+	walk(rv, "", &errs)
+	return errs
+}
+
+func walk(rv reflect.Value, prefix string, errs *[]apierror.FieldError) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := fieldName(sf)
+		if name == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+
+		// Struct embedded/bersarang ikut divalidasi dengan prefix nama field
+		if sf.Anonymous && fv.Kind() == reflect.Struct {
+			walk(fv, prefix, errs)
+			continue
+		}
+		if fv.Kind() == reflect.Struct && sf.Tag.Get("validate") == "" {
+			walk(fv, prefix+name+".", errs)
+			continue
+		}
+
+		if fe, ok := checkField(fv, sf.Tag.Get("validate")); !ok {
+			fe.Field = prefix + name
+			*errs = append(*errs, fe)
+		}
+	}
+}
+
+func fieldName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" {
+		return sf.Name
+	}
+	return name
+}
+
+// checkField berhenti di aturan pertama yang gagal, jadi satu field paling
+// banyak menghasilkan satu error.
+func checkField(fv reflect.Value, tag string) (apierror.FieldError, bool) {
+	if tag == "" {
+		return apierror.FieldError{}, true
+	}
+	rules := strings.Split(tag, ",")
+
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			if slices.Contains(rules, "required") {
+				return fail("required", "is required")
+			}
+			return apierror.FieldError{}, true
+		}
+		fv = fv.Elem()
+	}
+
+	for _, rule := range rules {
+		if rule == "omitempty" {
+			if isZero(fv) {
+				break
+			}
+			continue
+		}
+		name, param, _ := strings.Cut(rule, "=")
+		if fe, ok := apply(fv, name, param); !ok {
+			return fe, false
+		}
+	}
+	return apierror.FieldError{}, true
+}
+
+func apply(fv reflect.Value, rule, param string) (apierror.FieldError, bool) {
+	switch rule {
+	case "required":
+		if isZero(fv) {
+			return fail("required", "is required")
+		}
+	case "min":
+		n := mustFloat(param)
+		if size, isLen := measure(fv); size < n {
+			if isLen {
+				return failWith("min", "validation.min_length", fmt.Sprintf("must be at least %s characters", param), param)
+			}
+			return failWith("min", "validation.min", "must be at least "+param, param)
+		}
+	case "max":
+		n := mustFloat(param)
+		if size, isLen := measure(fv); size > n {
+			if isLen {
+				return failWith("max", "validation.max_length", fmt.Sprintf("must be at most %s characters", param), param)
+			}
+			return failWith("max", "validation.max", "must be at most "+param, param)
+		}
+	case "gt":
+		if size, _ := measure(fv); size <= mustFloat(param) {
+			return failWith("gt", "validation.gt", "must be greater than "+param, param)
+		}
+	case "oneof":
+		options := strings.Join(strings.Fields(param), ", ")
+		if !slices.Contains(strings.Fields(param), fmt.Sprint(fv.Interface())) {
+			return failWith("oneof", "validation.oneof", "must be one of: "+options, options)
+		}
+	case "email":
+		if s := fv.String(); s != "" {
+			if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+				return fail("email", "must be a valid email address")
+			}
+		}
+	case "uuid":
+		if s := fv.String(); s != "" && !uuidPattern.MatchString(s) {
+			return fail("uuid", "must be a valid UUID")
+		}
+	case "url":
+		if s := fv.String(); s != "" {
+			if u, err := url.Parse(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fail("url", "must be an http(s) URL")
+			}
+		}
+	default:
+		panic("validate: unknown rule " + strconv.Quote(rule))
+	}
+	return apierror.FieldError{}, true
+}
+
+func fail(code, message string) (apierror.FieldError, bool) {
+	return apierror.FieldError{Code: code, Message: message}, false
+}
+
+// failWith menyertakan parameter aturan supaya pesan bisa diterjemahkan, mis.
+// "minimal {param} karakter".
+func failWith(code, key, message, param string) (apierror.FieldError, bool) {
+	return apierror.FieldError{Code: code, Message: message, Key: key, Params: map[string]string{"param": param}}, false
+}
+
+func isZero(fv reflect.Value) bool {
+	if fv.Kind() == reflect.String {
+		return strings.TrimSpace(fv.String()) == ""
+	}
+	return fv.IsZero()
+}
+
+// measure mengembalikan panjang untuk string/slice (isLen true) atau nilai untuk angka.
+func measure(fv reflect.Value) (float64, bool) {
+	switch fv.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(fv.String())), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(fv.Len()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(fv.Int()), false
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(fv.Uint()), false
+	case reflect.Float32, reflect.Float64:
+		return fv.Float(), false
+	}
+	panic("validate: cannot measure " + fv.Kind().String())
+}
+
+func mustFloat(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		panic("validate: bad rule parameter " + strconv.Quote(s))
+	}
+	return f
+}
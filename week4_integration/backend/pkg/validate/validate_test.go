@@ -0,0 +1,121 @@
+package validate_test
+
+import (
+	"testing"
+
+	"backend/pkg/apierror"
+	"backend/pkg/validate"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type request struct {
+	Name     string   `json:"name" validate:"required,max=5"`
+	Email    string   `json:"email" validate:"omitempty,email"`
+	Price    float64  `json:"price" validate:"gt=0"`
+	Quantity int      `json:"quantity" validate:"min=1,max=10"`
+	Status   string   `json:"status" validate:"omitempty,oneof=pending done"`
+	UserID   string   `json:"user_id" validate:"omitempty,uuid"`
+	Website  string   `json:"website" validate:"url"`
+	Note     *string  `json:"note" validate:"omitempty,min=3"`
+	Tags     []string `json:"tags" validate:"max=2"`
+	Address  address  `json:"address"`
+	internal string   `validate:"required"`
+}
+
+func valid() request {
+	return request{Name: "Budi", Price: 1, Quantity: 1, Address: address{City: "Bandung"}}
+}
+
+func codes(errs []apierror.FieldError) map[string]string {
+	out := map[string]string{}
+	for _, e := range errs {
+		out[e.Field] = e.Code
+	}
+	return out
+}
+
+func TestStructValid(t *testing.T) {
+	r := valid()
+	note := "catatan"
+	r.Email, r.Status, r.UserID, r.Website, r.Note = "budi@example.com", "done", "3F2504E0-4F89-11D3-9A0C-0305E82C3301", "https://example.com", &note
+	if errs := validate.Struct(&r); errs != nil {
+		t.Errorf("errors = %+v", errs)
+	}
+	// Nilai (bukan pointer) juga diterima; field unexported dilewati
+	if errs := validate.Struct(valid()); errs != nil {
+		t.Errorf("errors = %+v", errs)
+	}
+	if errs := validate.Struct("not a struct"); errs != nil {
+		t.Errorf("non-struct errors = %+v", errs)
+	}
+}
+
+// Semua field diperiksa sekaligus, tapi satu field hanya satu error
+func TestStructCollectsAllFields(t *testing.T) {
+	short := "ab"
+	r := request{
+		Name:     "   ",
+		Email:    "Budi <budi@example.com>",
+		Price:    0,
+		Quantity: 11,
+		Status:   "shipped",
+		UserID:   "not-a-uuid",
+		Website:  "ftp://example.com",
+		Note:     &short,
+		Tags:     []string{"a", "b", "c"},
+	}
+	got := codes(validate.Struct(&r))
+	want := map[string]string{
+		"name":         "required",
+		"email":        "email",
+		"price":        "gt",
+		"quantity":     "max",
+		"status":       "oneof",
+		"user_id":      "uuid",
+		"website":      "url",
+		"note":         "min",
+		"tags":         "max",
+		"address.city": "required",
+	}
+	if len(got) != len(want) {
+		t.Errorf("errors = %v, want %v", got, want)
+	}
+	for field, code := range want {
+		if got[field] != code {
+			t.Errorf("%s = %q, want %q", field, got[field], code)
+		}
+	}
+}
+
+func TestStructMessageParams(t *testing.T) {
+	r := valid()
+	r.Name = "Bambang"
+	errs := validate.Struct(&r)
+	if len(errs) != 1 {
+		t.Fatalf("errors = %+v", errs)
+	}
+	// max pada string dihitung per karakter dan memakai key panjang
+	if e := errs[0]; e.Key != "validation.max_length" || e.Params["param"] != "5" || e.Message != "must be at most 5 characters" {
+		t.Errorf("error = %+v", e)
+	}
+
+	r = valid()
+	r.Name = "Ñåmé"
+	if errs := validate.Struct(&r); errs != nil {
+		t.Errorf("multibyte name errors = %+v", errs)
+	}
+}
+
+func TestStructUnknownRulePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("unknown rule did not panic")
+		}
+	}()
+	validate.Struct(struct {
+		Name string `validate:"shout"`
+	}{})
+}
@@ -0,0 +1,43 @@
+// Package version menyimpan info build yang diisi lewat ldflags, misalnya:
+//
+//	go build -ldflags "-X backend/pkg/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X backend/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get mengembalikan info build. Kalau ldflags tidak diisi (mis. build Vercel),
+// commit dan waktu diambil dari metadata VCS yang ditanam oleh go build.
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = s.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "dev"
+	}
+	return info
+}